@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/informers/externalversions"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/controller/claimtemplate"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/controller/deviceclass"
+)
+
+var flagKubeconfig string
+var flagWorkers int
+
+func init() {
+	flag.StringVar(&flagKubeconfig, "kubeconfig", "", "kubeconfig file")
+	flag.IntVar(&flagWorkers, "workers", 1, "number of workers for each controller")
+}
+
+func main() {
+	flag.Parse()
+
+	config, err := clientcmd.BuildConfigFromFlags("", flagKubeconfig)
+	if err != nil {
+		log.Fatalf("error loading kubeconfig: %v", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("error creating client: %v", err)
+	}
+
+	devMgmtClient, err := versioned.NewForConfig(config)
+	if err != nil {
+		log.Fatalf("error creating client: %v", err)
+	}
+
+	// Pods are read with the dynamic client, since they have fields that
+	// are not part of the real PodSpec, and so are the claims of all kinds,
+	// which the claim template controller handles alike.
+	dynamicFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
+	factory := externalversions.NewSharedInformerFactory(devMgmtClient, 0)
+
+	claimTemplateController, err := claimtemplate.NewController(dynamicClient,
+		dynamicFactory,
+		factory.Devmgmtproto().V1alpha1().DeviceClaimTemplates())
+	if err != nil {
+		log.Fatalf("error creating claim template controller: %v", err)
+	}
+
+	deviceClassController, err := deviceclass.NewController(devMgmtClient,
+		factory.Devmgmtproto().V1alpha1().DeviceClasses(),
+		factory.Devmgmtproto().V1alpha1().DeviceDrivers())
+	if err != nil {
+		log.Fatalf("error creating device class controller: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dynamicFactory.Start(ctx.Done())
+	factory.Start(ctx.Done())
+
+	go deviceClassController.Run(ctx, flagWorkers)
+	claimTemplateController.Run(ctx, flagWorkers)
+}
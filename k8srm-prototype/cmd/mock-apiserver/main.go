@@ -1,39 +1,92 @@
 package main
 
 import (
-	"k8s.io/apimachinery/pkg/api/meta"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"context"
+	"errors"
+	"flag"
 	"log"
-	"sigs.k8s.io/kubebuilder-declarative-pattern/mockkubeapiserver"
-	"sync"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/mockapiserver"
 )
 
+var flagAddr, flagSeed, flagSnapshot string
+
+func init() {
+	flag.StringVar(&flagAddr, "addr", ":55441", "address to listen on")
+	flag.StringVar(&flagSeed, "seed", "", "manifest file, or directory of manifest files, to load at startup")
+	flag.StringVar(&flagSnapshot, "snapshot", "", "file to save all objects to on shutdown, and to load them from at startup instead of -seed if it exists")
+}
+
 func main() {
-	var wg sync.WaitGroup
+	flag.Parse()
 
-	k8s, err := mockkubeapiserver.NewMockKubeAPIServer(":55441")
+	server, err := mockapiserver.New()
 	if err != nil {
 		log.Fatalf("error creating mock-apiserver: %v", err)
 	}
 
-	k8s.RegisterType(schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Namespace"}, "namespaces", meta.RESTScopeRoot)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Secret"}, "secrets", meta.RESTScopeNamespace)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"}, "configmaps", meta.RESTScopeNamespace)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}, "pods", meta.RESTScopeNamespace)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Node"}, "nodes", meta.RESTScopeNamespace)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "foozer.example.com", Version: "v1alpha1", Kind: "FoozerConfig"}, "foozerconfigs", meta.RESTScopeNamespace)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DeviceDriver"}, "devicedrivers", meta.RESTScopeRoot)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DeviceClass"}, "deviceclasses", meta.RESTScopeRoot)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DeviceClaim"}, "deviceclaims", meta.RESTScopeNamespace)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DevicePrivilegedClaim"}, "deviceprivilegedclaims", meta.RESTScopeNamespace)
-	k8s.RegisterType(schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DevicePool"}, "devicepools", meta.RESTScopeRoot)
-
-	wg.Add(1)
-	addr, err := k8s.StartServing()
+	// A snapshot already contains anything that was seeded when it was
+	// saved, so the seed is only used when there is no snapshot yet.
+	load := flagSeed
+	if flagSnapshot != "" {
+		if _, err := os.Stat(flagSnapshot); err == nil {
+			load = flagSnapshot
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.Fatalf("error reading snapshot: %v", err)
+		}
+	}
+
+	if load != "" {
+		objs, err := mockapiserver.ReadManifests(load)
+		if err != nil {
+			log.Fatalf("error reading manifests: %v", err)
+		}
+		if err := server.AddObjects(objs); err != nil {
+			log.Fatalf("error loading %s: %v", load, err)
+		}
+		log.Printf("loaded %d objects from %s", len(objs), load)
+	}
+
+	listener, err := net.Listen("tcp", flagAddr)
 	if err != nil {
 		log.Fatalf("error starting mock-apiserver: %v", err)
 	}
-	log.Println("addr = ", addr)
+	log.Println("addr = ", listener.Addr())
+
+	httpServer := &http.Server{Handler: server}
+	go func() {
+		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("error serving mock-apiserver: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	stop()
+
+	// Watches never finish on their own, so they are cut off after a
+	// while.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		httpServer.Close()
+	}
 
-	wg.Wait()
+	if flagSnapshot != "" {
+		objs, err := server.Objects(context.Background())
+		if err != nil {
+			log.Fatalf("error listing objects: %v", err)
+		}
+		if err := mockapiserver.WriteManifests(flagSnapshot, objs); err != nil {
+			log.Fatalf("error writing snapshot: %v", err)
+		}
+		log.Printf("saved %d objects to %s", len(objs), flagSnapshot)
+	}
 }
@@ -1,28 +1,35 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 
 	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
 	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/gen"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/schedule"
 
 	"sigs.k8s.io/yaml"
 )
 
-var flagPodName, flagKubeconfig string
+var flagPodName, flagNamespace, flagKubeconfig, flagSolver, flagAllocationPolicy, flagScorer, flagAllocator string
 var flagVerbose bool
 
 func init() {
 	flag.StringVar(&flagKubeconfig, "kubeconfig", "", "kubeconfig file")
 	flag.StringVar(&flagPodName, "pod", "", "name of the pod to try to schedule")
+	flag.StringVar(&flagNamespace, "namespace", "default", "namespace of the pod")
+	flag.StringVar(&flagSolver, "solver", string(schedule.SolverGreedy), "solver to use for multiple claims: greedy or exhaustive")
+	flag.StringVar(&flagAllocationPolicy, "allocation-policy", string(schedule.AllocationPolicyMinimum), "number of devices to allocate for claims with a range: minimum or maximum")
+	flag.StringVar(&flagScorer, "scorer", "fewest-pools", "scoring strategy: fewest-pools, most-allocated, least-allocated, or topology=<attr>[,<attr>...]")
+	flag.StringVar(&flagAllocator, "allocator", "pool-set", "device allocation algorithm: pool-set or flattened")
 	flag.BoolVar(&flagVerbose, "v", false, "verbose output")
 	flag.Usage = usage
 }
 
 func usage() {
-	fmt.Fprintf(flag.CommandLine.Output(), "usage: %s -kubeconfig <file> -pod <name> pod\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: %s -kubeconfig <file> [-namespace <ns>] -pod <name> pod\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "       %s gen-example <shape>\n", os.Args[0])
 	flag.PrintDefaults()
 }
@@ -43,9 +50,12 @@ func genCapacityExample(shape string) {
 		fmt.Printf("unknown shape %q\n", shape)
 	}
 
-	if pools != nil {
-		b, _ := yaml.Marshal(pools)
-		fmt.Println(string(b))
+	// Print each pool as a separate document, so that the output can be
+	// applied directly with kubectl.
+	for _, p := range pools {
+		b, _ := yaml.Marshal(p)
+		fmt.Println("---")
+		fmt.Print(string(b))
 	}
 }
 
@@ -67,8 +77,19 @@ func main() {
 		genCapacityExample(shape)
 		break
 	case "pod":
-		fmt.Fprintf(flag.CommandLine.Output(), "not implemented yet\n")
-		os.Exit(1)
+		if flagPodName == "" {
+			usage()
+			os.Exit(1)
+		}
+		client, err := newClients(flagKubeconfig)
+		if err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "%s\n", err)
+			os.Exit(1)
+		}
+		if err := schedulePod(context.Background(), client, flagNamespace, flagPodName); err != nil {
+			fmt.Fprintf(flag.CommandLine.Output(), "%s\n", err)
+			os.Exit(1)
+		}
 		break
 	default:
 		usage()
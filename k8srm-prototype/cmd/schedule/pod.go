@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/podscheduler"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/schedule"
+
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/yaml"
+)
+
+func newClients(kubeconfig string) (*podscheduler.Clients, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig: %w", err)
+	}
+
+	return podscheduler.NewClients(config)
+}
+
+// schedulePod schedules the Pod with the options from the flags, and prints
+// the results.
+func schedulePod(ctx context.Context, client *podscheduler.Clients, namespace, name string) error {
+	scorer, err := newScorer(flagScorer)
+	if err != nil {
+		return err
+	}
+
+	allocator, err := newAllocator(flagAllocator)
+	if err != nil {
+		return err
+	}
+
+	solver, err := newSolver(flagSolver)
+	if err != nil {
+		return err
+	}
+
+	policy, err := newAllocationPolicy(flagAllocationPolicy)
+	if err != nil {
+		return err
+	}
+
+	result, err := podscheduler.SchedulePod(ctx, client, namespace, name,
+		schedule.WithSolver(solver),
+		schedule.WithAllocationPolicy(policy),
+		schedule.WithScorer(scorer),
+		schedule.WithAllocator(allocator))
+	if err != nil && !errors.Is(err, podscheduler.ErrUnschedulable) {
+		return err
+	}
+
+	if flagVerbose {
+		b, _ := yaml.Marshal(result.EffectiveClaims)
+		fmt.Printf("effective device claims:\n%s\n", string(b))
+
+		b, _ = yaml.Marshal(result.NodeResults)
+		fmt.Println(string(b))
+	} else {
+		for _, nr := range result.NodeResults {
+			fmt.Println(nr.Summary())
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("pod %s/%s scheduled to node %s\n", namespace, name, result.NodeName)
+
+	return nil
+}
+
+// newScorer returns the scheduler Scorer with the given name.
+func newScorer(name string) (schedule.Scorer, error) {
+	switch {
+	case name == "fewest-pools":
+		return schedule.FewestPoolsScorer{}, nil
+	case name == "most-allocated":
+		return schedule.MostAllocatedScorer{}, nil
+	case name == "least-allocated":
+		return schedule.LeastAllocatedScorer{}, nil
+	case strings.HasPrefix(name, "topology="):
+		return schedule.TopologyScorer{
+			Attributes: strings.Split(strings.TrimPrefix(name, "topology="), ","),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown scorer %q", name)
+}
+
+// newAllocator returns the scheduler Allocator with the given name.
+func newAllocator(name string) (schedule.Allocator, error) {
+	switch name {
+	case "pool-set":
+		return schedule.PoolSetAllocator{}, nil
+	case "flattened":
+		return schedule.FlattenedAllocator{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown allocator %q", name)
+}
+
+// newSolver returns the scheduler SolverMode with the given name.
+func newSolver(name string) (schedule.SolverMode, error) {
+	switch mode := schedule.SolverMode(name); mode {
+	case schedule.SolverGreedy, schedule.SolverExhaustive:
+		return mode, nil
+	}
+
+	return "", fmt.Errorf("unknown solver %q, must be %s or %s", name, schedule.SolverGreedy, schedule.SolverExhaustive)
+}
+
+// newAllocationPolicy returns the scheduler AllocationPolicy with the given
+// name.
+func newAllocationPolicy(name string) (schedule.AllocationPolicy, error) {
+	switch policy := schedule.AllocationPolicy(name); policy {
+	case schedule.AllocationPolicyMinimum, schedule.AllocationPolicyMaximum:
+		return policy, nil
+	}
+
+	return "", fmt.Errorf("unknown allocation policy %q, must be %s or %s", name, schedule.AllocationPolicyMinimum, schedule.AllocationPolicyMaximum)
+}
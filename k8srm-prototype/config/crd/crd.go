@@ -0,0 +1,41 @@
+// Package crd contains the CustomResourceDefinitions for the kinds in the
+// devmgmtproto.k8s.io API group. The YAML files are generated from the types
+// in pkg/api by `make generate`, and should not be edited by hand.
+package crd
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed *.yaml
+var files embed.FS
+
+// CustomResourceDefinitions returns the CRDs for every kind in the API group,
+// sorted by the name of their files.
+func CustomResourceDefinitions() ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	names, err := fs.Glob(files, "*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for _, name := range names {
+		data, err := files.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.UnmarshalStrict(data, crd); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", name, err)
+		}
+		crds = append(crds, crd)
+	}
+
+	return crds, nil
+}
@@ -0,0 +1,93 @@
+#!/usr/bin/env bash
+
+# Copyright 2024 The Kubernetes Authors
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#      http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Regenerates the deepcopy functions for the types in pkg/api, the
+# clientset, listers and informers for them in pkg/client, and their
+# CustomResourceDefinitions in config/crd.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+CODEGEN_VERSION=v0.30.0
+CONTROLLER_GEN_VERSION=v0.13.0
+MODULE=github.com/kubernetes-sigs/wg-device-management/k8srm-prototype
+ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+BIN="${ROOT}/hack/bin"
+HEADER="${ROOT}/hack/boilerplate.go.txt"
+
+# The generators find the group and version of the types from the last two
+# elements of their package path, so they are given a copy of pkg/api that
+# looks like devmgmtproto/v1alpha1, and the imports of the copy are replaced
+# afterwards.
+STAGE=hack/codegen/devmgmtproto/v1alpha1
+OUT=$(mktemp -d)
+trap 'rm -rf "${OUT}" "${ROOT}/hack/codegen"' EXIT
+
+cd "${ROOT}"
+
+for gen in deepcopy-gen client-gen lister-gen informer-gen; do
+	if [ ! -x "${BIN}/${gen}" ]; then
+		GOBIN="${BIN}" go install "k8s.io/code-generator/cmd/${gen}@${CODEGEN_VERSION}"
+	fi
+done
+if [ ! -x "${BIN}/controller-gen" ]; then
+	GOBIN="${BIN}" go install "sigs.k8s.io/controller-tools/cmd/controller-gen@${CONTROLLER_GEN_VERSION}"
+fi
+
+"${BIN}/deepcopy-gen" \
+	--go-header-file "${HEADER}" \
+	--output-file zz_generated.deepcopy.go \
+	"${MODULE}/pkg/api"
+
+mkdir -p "${STAGE}"
+for f in pkg/api/*.go; do
+	case "${f}" in
+	*_test.go) ;;
+	*) cp "${f}" "${STAGE}/" ;;
+	esac
+done
+
+"${BIN}/client-gen" \
+	--go-header-file "${HEADER}" \
+	--output-dir "${OUT}/clientset" \
+	--output-pkg "${MODULE}/pkg/client/clientset" \
+	--clientset-name versioned \
+	--input-base "${MODULE}/hack/codegen" \
+	--input devmgmtproto/v1alpha1
+
+"${BIN}/lister-gen" \
+	--go-header-file "${HEADER}" \
+	--output-dir "${OUT}/listers" \
+	--output-pkg "${MODULE}/pkg/client/listers" \
+	"${MODULE}/${STAGE}"
+
+"${BIN}/informer-gen" \
+	--go-header-file "${HEADER}" \
+	--output-dir "${OUT}/informers" \
+	--output-pkg "${MODULE}/pkg/client/informers" \
+	--versioned-clientset-package "${MODULE}/pkg/client/clientset/versioned" \
+	--listers-package "${MODULE}/pkg/client/listers" \
+	"${MODULE}/${STAGE}"
+
+rm -rf pkg/client
+cp -r "${OUT}" pkg/client
+find pkg/client -name '*.go' -exec sed -i.bak "s|${MODULE}/${STAGE}|${MODULE}/pkg/api|g" {} +
+find pkg/client -name '*.bak' -delete
+gofmt -s -w pkg/client
+
+rm -f config/crd/*.yaml
+"${BIN}/controller-gen" crd:generateEmbeddedObjectMeta=true paths=./pkg/api/... output:crd:dir=config/crd
@@ -13,6 +13,18 @@ const (
 // devices are divided into pools is driver-specific, but typically the
 // expectation would a be a pool per identical collection of devices, per node.
 // It is fine to have more than one pool for a given node, for the same driver.
+//
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Driver",type=string,JSONPath=`.spec.driver`
+// +kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+// +kubebuilder:printcolumn:name="Count",type=integer,JSONPath=`.spec.count`
+// +kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.availableDevices`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type DevicePool struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -21,6 +33,16 @@ type DevicePool struct {
 	Status DevicePoolStatus `json:"status,omitempty"`
 }
 
+// DevicePoolList is a collection of DevicePools.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DevicePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DevicePool `json:"items"`
+}
+
 // DevicePoolSpec identifies the driver and contains the data for the pool
 // prior to any allocations.
 // NOTE: It's not clear that spec/status is the right model for this data.
@@ -30,6 +52,24 @@ type DevicePoolSpec struct {
 	// +optional
 	NodeName *string `json:"nodeName,omitempty"`
 
+	// NodeSelector limits the nodes from which a network attached pool
+	// may be reached, by the labels of the nodes. This is only used if
+	// NodeName is empty.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// ReachabilityAttributes limits the nodes from which a network
+	// attached pool may be reached to those with a local pool that has
+	// the same values for these attributes as this pool. For example,
+	// "rack" would allow a pool to be used only from nodes with devices
+	// in the same rack. This is only used if NodeName is empty.
+	//
+	// If neither NodeSelector nor ReachabilityAttributes is set, a network
+	// attached pool may be reached from any node. If both are set, a node
+	// must satisfy both.
+	// +optional
+	ReachabilityAttributes []string `json:"reachabilityAttributes,omitempty"`
+
 	// Driver is the name of the DeviceDriver that created this object and
 	// owns the data in it.
 	// +required
@@ -54,6 +94,8 @@ type DevicePoolStatus struct {
 }
 
 // Attribute capture the name, value, and type of an device attribute.
+//
+// +kubebuilder:validation:XValidation:rule="[has(self.stringValue), has(self.intValue), has(self.quantityValue), has(self.semVerValue)].filter(x, x).size() == 1",message="exactly one of stringValue, intValue, quantityValue, or semVerValue must be set"
 type Attribute struct {
 	Name string `json:"name"`
 
@@ -64,6 +106,22 @@ type Attribute struct {
 	SemVerValue   *SemVer            `json:"semVerValue,omitempty"`
 }
 
+// AttributeType is the type of the value of an attribute.
+type AttributeType string
+
+const (
+	AttributeTypeString   AttributeType = "String"
+	AttributeTypeInt      AttributeType = "Int"
+	AttributeTypeQuantity AttributeType = "Quantity"
+	AttributeTypeSemVer   AttributeType = "SemVer"
+)
+
+// AttributeSchema declares the name and type of an attribute.
+type AttributeSchema struct {
+	Name string        `json:"name"`
+	Type AttributeType `json:"type"`
+}
+
 func (a Attribute) Equal(b Attribute) bool {
 	if a.Name != b.Name {
 		return false
@@ -92,6 +150,6 @@ func (a Attribute) EqualValue(b Attribute) bool {
 	return false
 }
 
-// SemVer represents a semantic version value. In this prototype it is just a
-// string.
+// SemVer represents a semantic version value, as defined by
+// https://semver.org. It is stored as a string; use Parse to compare versions.
 type SemVer string
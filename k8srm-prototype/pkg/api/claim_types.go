@@ -8,6 +8,13 @@ import (
 // DeviceDriver is a vendor provided resource that registers a given
 // driver with the cluster.
 // Cluster scoped.
+//
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type DeviceDriver struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -26,12 +33,38 @@ type DeviceDriver struct {
 	//
 	// +required
 	DeviceTypes []string `json:"deviceTypes,omitempty"`
+
+	// AttributeSchema declares the type of each attribute that the driver
+	// publishes in its pools. When present, constraints on devices of this
+	// driver can be type-checked before they are ever evaluated, catching
+	// mistakes like misspelled attribute names or comparing a string to
+	// an int.
+	//
+	// +optional
+	AttributeSchema []AttributeSchema `json:"attributeSchema,omitempty"`
+}
+
+// DeviceDriverList is a collection of DeviceDrivers.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DeviceDriverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DeviceDriver `json:"items"`
 }
 
 // DeviceClass is a vendor or admin-provided resource that contains
 // contraint and configuration information. Essentially, it is a re-usable
 // collection of predefined data that device claims may use.
 // Cluster scoped.
+//
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type DeviceClass struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -40,7 +73,19 @@ type DeviceClass struct {
 	Status DeviceClassStatus `json:"status,omitempty"`
 }
 
+// DeviceClassList is a collection of DeviceClasses.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DeviceClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DeviceClass `json:"items"`
+}
+
 // DeviceClassSpec provides the details of the DeviceClass.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.minDeviceCount) || !has(self.maxDeviceCount) || self.minDeviceCount <= self.maxDeviceCount",message="minDeviceCount must not be greater than maxDeviceCount"
 type DeviceClassSpec struct {
 	// DeviceType is a driver-independent classification of the device.
 	// This may be used instead of specifying the Driver explicitly, so that
@@ -51,13 +96,13 @@ type DeviceClassSpec struct {
 	// device to indicate device functions.
 	//
 	// +required
-	DeviceType string `json:deviceType,omitempty`
+	DeviceType string `json:"deviceType,omitempty"`
 
 	// Driver specifies the driver that should handle this class of devices.
 	// When a DeviceClaim uses this class, only devices published by the
 	// specified driver will be considered.
 	// +optional
-	Driver *string `json:driver,omitempty`
+	Driver *string `json:"driver,omitempty"`
 
 	// Constraints is a CEL expression that operates on device attributes,
 	// and must evaluate to true for a device to be considered. It will be
@@ -107,14 +152,39 @@ type DeviceClassStatus struct {
 	// Conditions contains the latest observation of the class's state.
 	// A class will be in Ready state if at least one DeviceDriver is
 	// registered to handle the class.
-	Conditions []metav1.Condition `json:"conditions"`
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// Drivers contains the list of drivers that can handle this class.
 	Drivers []string `json:"drivers,omitempty"`
 }
 
+// The type of the condition that tells whether a DeviceClass can be used, and
+// the reasons it may have.
+const (
+	DeviceClassConditionReady = "Ready"
+
+	// DeviceClassReasonDriverRegistered means that at least one driver can
+	// handle the class.
+	DeviceClassReasonDriverRegistered = "DriverRegistered"
+
+	// DeviceClassReasonNoDriverRegistered means that no driver handles the
+	// DeviceType of the class, or that the Driver of the class is not
+	// registered.
+	DeviceClassReasonNoDriverRegistered = "NoDriverRegistered"
+
+	// DeviceClassReasonDriverMismatch means that the Driver of the class is
+	// registered, but does not handle the DeviceType of the class.
+	DeviceClassReasonDriverMismatch = "DriverMismatch"
+)
+
 // DeviceClaim is used to specify a request for a set of devices.
 // Namespace scoped.
+//
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type DeviceClaim struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -123,8 +193,20 @@ type DeviceClaim struct {
 	Status DeviceClaimStatus `json:"status,omitempty"`
 }
 
+// DeviceClaimList is a collection of DeviceClaims.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DeviceClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DeviceClaim `json:"items"`
+}
+
 // DeviceClaimSpec details the requirements that devices chosen
 // to satisfy this claim must meet.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.minDeviceCount) || !has(self.maxDeviceCount) || self.minDeviceCount <= self.maxDeviceCount",message="minDeviceCount must not be greater than maxDeviceCount"
 type DeviceClaimSpec struct {
 	// DeviceClass is the name of the DeviceClass containing the basic information
 	// about the device being requested.
@@ -213,7 +295,11 @@ type DeviceClaimStatus struct {
 // managed by a given driver on a given node. It intentionally does not require
 // a class, though it does allow some flexibility with the specification of
 // Constraints and Configs.
-
+//
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type DevicePrivilegedClaim struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -222,6 +308,16 @@ type DevicePrivilegedClaim struct {
 	Status DevicePrivilegedClaimStatus `json:"status,omitempty"`
 }
 
+// DevicePrivilegedClaimList is a collection of DevicePrivilegedClaims.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DevicePrivilegedClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DevicePrivilegedClaim `json:"items"`
+}
+
 // DevicePrivilegedClaimSpec contains the details of the privileged claim.
 type DevicePrivilegedClaimSpec struct {
 	// Driver will limit the scope of devices considered to only those
@@ -263,6 +359,11 @@ type DevicePrivilegedClaimStatus struct {
 // than changing how the devices are published by drivers, we just change
 // they are consumed. This allows us to combine claims into a group, and apply
 // additional constraints across the group.
+//
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type DeviceSetClaim struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -271,6 +372,16 @@ type DeviceSetClaim struct {
 	Status DeviceSetClaimStatus `json:"status,omitempty"`
 }
 
+// DeviceSetClaimList is a collection of DeviceSetClaims.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DeviceSetClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DeviceSetClaim `json:"items"`
+}
+
 // DeviceSetClaimSpec contains the details for requesting a set of devices
 // as a unit.
 type DeviceSetClaimSpec struct {
@@ -289,7 +400,7 @@ type DeviceSetClaimSpec struct {
 
 	MatchAttributes []string `json:"matchAttributes,omitempty"`
 
-	ClaimSpec []DeviceClaimSpec `json:claimSpec,omitempty"`
+	ClaimSpec []DeviceClaimSpec `json:"claimSpec,omitempty"`
 }
 
 type DeviceSetClaimStatus struct {
@@ -299,6 +410,11 @@ type DeviceSetClaimStatus struct {
 // DeviceClaimTemplate is used to generate claims along with Pods. These
 // generated claims have the same lifecycle as the Pod.
 // TODO: Could we just use a DeviceClaim here? Or is that too confusing?
+//
+// +genclient
+// +genclient:noStatus
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type DeviceClaimTemplate struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -306,10 +422,25 @@ type DeviceClaimTemplate struct {
 	Spec DeviceClaimTemplateSpec `json:"spec,omitempty"`
 }
 
+// DeviceClaimTemplateList is a collection of DeviceClaimTemplates.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DeviceClaimTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DeviceClaimTemplate `json:"items"`
+}
+
 // DeviceClaimTemplateSpec contains the information needed to generate
 // DeviceClaims, DevicePrivilegedClaims, or DeviceSetClaims.
+//
+// +kubebuilder:validation:XValidation:rule="[has(self.claimSpec), has(self.privilegedClaimSpec), has(self.setClaimSpec)].filter(x, x).size() == 1",message="exactly one of claimSpec, privilegedClaimSpec, or setClaimSpec must be set"
 type DeviceClaimTemplateSpec struct {
-	metav1.ObjectMeta
+	// ObjectMeta contains labels and annotations that will be copied into
+	// the generated claims.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	// Exactly one of these must be populated
 	DeviceClaimSpec           *DeviceClaimSpec           `json:"claimSpec,omitempty"`
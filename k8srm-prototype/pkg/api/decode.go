@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"gopkg.in/yaml.v3"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// newObjectForKind returns a new, empty object of each kind in the
+// DevMgmtAPIVersion.
+var newObjectForKind = map[string]func() any{
+	"DeviceDriver":          func() any { return &DeviceDriver{} },
+	"DeviceClass":           func() any { return &DeviceClass{} },
+	"DeviceClaim":           func() any { return &DeviceClaim{} },
+	"DevicePrivilegedClaim": func() any { return &DevicePrivilegedClaim{} },
+	"DeviceSetClaim":        func() any { return &DeviceSetClaim{} },
+	"DeviceClaimTemplate":   func() any { return &DeviceClaimTemplate{} },
+	"DevicePool":            func() any { return &DevicePool{} },
+}
+
+// DecodeStrict decodes a stream of YAML documents. Objects of the
+// DevMgmtAPIVersion are decoded into a pointer to their type, and any field
+// that the type does not have is an error, so that misspelled fields are not
+// silently dropped. Other objects, such as Pods, are decoded into an
+// *unstructured.Unstructured without any checks.
+//
+// Unknown fields are reported with their line number, and all of them are
+// reported at once. Other errors are reported with the line number of the
+// start of the document.
+func DecodeStrict(data []byte) ([]any, error) {
+	var objs []any
+	var errs []error
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(doc.Content) == 0 {
+			continue
+		}
+
+		obj, err := decodeDocument(doc.Content[0])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		objs = append(objs, obj)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return objs, nil
+}
+
+// UnmarshalStrict decodes a single YAML document into obj, which must be a
+// pointer to an API type, failing on any unknown fields.
+func UnmarshalStrict(data []byte, obj any) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if len(doc.Content) == 0 {
+		return fmt.Errorf("empty document")
+	}
+
+	return decodeInto(doc.Content[0], obj)
+}
+
+func decodeDocument(node *yaml.Node) (any, error) {
+	apiVersion := mappingValue(node, "apiVersion")
+	kind := mappingValue(node, "kind")
+
+	if apiVersion != DevMgmtAPIVersion {
+		obj := &unstructured.Unstructured{}
+		if err := decodeInto(node, &obj.Object); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+
+	newObject, ok := newObjectForKind[kind]
+	if !ok {
+		return nil, fmt.Errorf("line %d: unknown kind %q for %s", node.Line, kind, apiVersion)
+	}
+
+	obj := newObject()
+	if err := decodeInto(node, obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// decodeInto checks the node for unknown fields, and then decodes it using the
+// JSON tags of obj, as with sigs.k8s.io/yaml.
+func decodeInto(node *yaml.Node, obj any) error {
+	var errs []error
+	checkFields(node, reflect.TypeOf(obj), "", &errs)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	b, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	if err := sigsyaml.UnmarshalStrict(b, obj); err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+
+	return nil
+}
+
+// mappingValue returns the scalar value of key in a mapping node, or the empty
+// string.
+func mappingValue(node *yaml.Node, key string) string {
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1].Value
+		}
+	}
+
+	return ""
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// checkFields walks the node alongside the Go type it will be decoded into,
+// reporting every mapping key that has no corresponding field. Values that
+// decode themselves, such as quantities and raw extensions, are not checked.
+// Type mismatches are left to the decoder.
+func checkFields(node *yaml.Node, t reflect.Type, path string, errs *[]error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+
+		fields := jsonFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := joinPath(path, key.Value)
+			ft, ok := fields[key.Value]
+			if !ok {
+				*errs = append(*errs, fmt.Errorf("line %d: unknown field %q", key.Line, fieldPath))
+				continue
+			}
+			checkFields(value, ft, fieldPath, errs)
+		}
+
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkFields(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value), errs)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+
+		for i, item := range node.Content {
+			checkFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// jsonFields returns the types of the fields of the struct, keyed by their JSON
+// names. Fields of inlined and untagged embedded structs are included.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && (name == "" || opts == "inline") {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for n, t := range jsonFields(ft) {
+					fields[n] = t
+				}
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+
+	return fields
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+
+	return path + "." + field
+}
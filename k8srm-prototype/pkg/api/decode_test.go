@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"gopkg.in/yaml.v3"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+func TestDecodeStrict(t *testing.T) {
+	testCases := map[string]struct {
+		data      string
+		expected  []any
+		expectErr string
+	}{
+		"valid class": {
+			data: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClass
+metadata:
+  name: gpu
+spec:
+  deviceType: gpu
+  maxDeviceCount: 1
+`,
+			expected: []any{
+				&DeviceClass{
+					TypeMeta:   metav1.TypeMeta{APIVersion: DevMgmtAPIVersion, Kind: "DeviceClass"},
+					ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+					Spec: DeviceClassSpec{
+						DeviceType:     "gpu",
+						MaxDeviceCount: ptr(1),
+					},
+				},
+			},
+		},
+		"unknown spec field": {
+			data: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClass
+metadata:
+  name: gpu
+spec:
+  deviceType: gpu
+  deviceMaxCount: 1
+`,
+			expectErr: `line 8: unknown field "spec.deviceMaxCount"`,
+		},
+		"unknown nested field": {
+			data: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DevicePool
+metadata:
+  name: pool
+spec:
+  driver: example.com-foozer
+  attributes:
+  - name: model
+    stringValue: foozer-1000
+  - name: memory
+    quantity: 40Gi
+`,
+			expectErr: `line 12: unknown field "spec.attributes[1].quantity"`,
+		},
+		"all unknown fields are reported": {
+			data: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClass
+metadata:
+  name: gpu
+  labelz: {}
+spec:
+  contstraints: "device.vendor == 'example.com'"
+---
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClaim
+metadata:
+  name: claim
+spec:
+  deviceClas: gpu
+`,
+			expectErr: "line 6: unknown field \"metadata.labelz\"\n" +
+				"line 8: unknown field \"spec.contstraints\"\n" +
+				"line 15: unknown field \"spec.deviceClas\"",
+		},
+		"unknown kind": {
+			data: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceWidget
+`,
+			expectErr: `line 2: unknown kind "DeviceWidget" for devmgmtproto.k8s.io/v1alpha1`,
+		},
+		"wrong type": {
+			data: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClass
+spec:
+  maxDeviceCount: one
+`,
+			expectErr: "line 2: error unmarshaling JSON: while decoding JSON: json: cannot unmarshal string into Go struct field .spec.maxDeviceCount of type int",
+		},
+		"other objects are unstructured": {
+			data: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+`,
+			expected: []any{
+				&unstructured.Unstructured{
+					Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata":   map[string]any{"name": "config"},
+						"data":       map[string]any{"key": "value"},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := DecodeStrict([]byte(tc.data))
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, objs)
+		})
+	}
+}
+
+// TestDecodeTestdata decodes every file in testdata, and checks that encoding
+// the objects again keeps everything in the original files.
+func TestDecodeTestdata(t *testing.T) {
+	files, err := filepath.Glob("../../testdata/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			objs, err := DecodeStrict(data)
+			require.NoError(t, err)
+
+			// The original documents are compared as generic values,
+			// normalized by a trip through JSON like the objects.
+			var originals []any
+			decoder := yaml.NewDecoder(bytes.NewReader(data))
+			for {
+				var doc any
+				err := decoder.Decode(&doc)
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+
+				b, err := sigsyaml.Marshal(doc)
+				require.NoError(t, err)
+
+				var original any
+				require.NoError(t, sigsyaml.Unmarshal(b, &original))
+				originals = append(originals, original)
+			}
+			require.Len(t, objs, len(originals))
+
+			for i, obj := range objs {
+				b, err := sigsyaml.Marshal(obj)
+				require.NoError(t, err)
+
+				var roundTripped any
+				require.NoError(t, sigsyaml.Unmarshal(b, &roundTripped))
+
+				requireSubset(t, originals[i], roundTripped, "")
+			}
+		})
+	}
+}
+
+// requireSubset checks that every value in expected is also in actual. Extra
+// values in actual, such as empty statuses, are allowed.
+func requireSubset(t *testing.T, expected, actual any, path string) {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		require.True(t, ok, "%s: expected a map, got %v", path, actual)
+		for k, v := range e {
+			av, ok := a[k]
+			require.True(t, ok, "%s.%s was lost", path, k)
+			requireSubset(t, v, av, path+"."+k)
+		}
+	case []any:
+		a, ok := actual.([]any)
+		require.True(t, ok, "%s: expected a list, got %v", path, actual)
+		require.Len(t, a, len(e), "%s", path)
+		for i := range e {
+			requireSubset(t, e[i], a[i], fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		require.True(t, reflect.DeepEqual(expected, actual), "%s: expected %v, got %v", path, expected, actual)
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"fmt"
+)
+
+// DefaultMinDeviceCount is the number of devices selected for a claim when
+// neither the claim nor its class specify a minimum.
+const DefaultMinDeviceCount = 1
+
+// EffectiveClaim resolves the claim against its class, returning a copy of the
+// claim in which every spec field has its effective value:
+//
+//   - Driver is the driver of the claim or, if that is not set, of the class.
+//     It is nil if neither names a driver, in which case any driver for the
+//     DeviceType of the class may be used.
+//   - Constraints are the class and claim constraints, ANDed together.
+//   - MinDeviceCount is the claim minimum, or else the class minimum, or else
+//     DefaultMinDeviceCount.
+//   - MaxDeviceCount is the claim maximum, or else the class maximum. It is nil
+//     if there is no maximum.
+//   - MatchAttributes is the union of the class and claim MatchAttributes.
+//
+// The class Configs are not merged, since they refer to objects in other
+// namespaces. An error is returned if the claim conflicts with the class.
+func EffectiveClaim(claim *DeviceClaim, class *DeviceClass) (*DeviceClaim, error) {
+	if class == nil {
+		return nil, fmt.Errorf("device class %q not found", claim.Spec.DeviceClass)
+	}
+
+	effective := &DeviceClaim{
+		TypeMeta:   claim.TypeMeta,
+		ObjectMeta: claim.ObjectMeta,
+		Status:     claim.Status,
+		Spec: DeviceClaimSpec{
+			DeviceClass: claim.Spec.DeviceClass,
+			Configs:     append([]DeviceConfigReference(nil), claim.Spec.Configs...),
+		},
+	}
+	spec := &effective.Spec
+
+	switch {
+	case claim.Spec.Driver != nil && class.Spec.Driver != nil && *claim.Spec.Driver != *class.Spec.Driver:
+		return nil, fmt.Errorf("claim driver %q does not match class driver %q", *claim.Spec.Driver, *class.Spec.Driver)
+	case claim.Spec.Driver != nil:
+		spec.Driver = copyPtr(claim.Spec.Driver)
+	case class.Spec.Driver != nil:
+		spec.Driver = copyPtr(class.Spec.Driver)
+	}
+
+	spec.Constraints = andConstraints(class.Spec.Constraints, claim.Spec.Constraints)
+
+	minCount := DefaultMinDeviceCount
+	if class.Spec.MinDeviceCount != nil {
+		minCount = *class.Spec.MinDeviceCount
+	}
+	if claim.Spec.MinDeviceCount != nil {
+		if class.Spec.MinDeviceCount != nil && *claim.Spec.MinDeviceCount < *class.Spec.MinDeviceCount {
+			return nil, fmt.Errorf("claim MinDeviceCount %d is less than class MinDeviceCount %d", *claim.Spec.MinDeviceCount, *class.Spec.MinDeviceCount)
+		}
+		minCount = *claim.Spec.MinDeviceCount
+	}
+	spec.MinDeviceCount = &minCount
+
+	spec.MaxDeviceCount = copyPtr(class.Spec.MaxDeviceCount)
+	if claim.Spec.MaxDeviceCount != nil {
+		if class.Spec.MaxDeviceCount != nil && *claim.Spec.MaxDeviceCount > *class.Spec.MaxDeviceCount {
+			return nil, fmt.Errorf("claim MaxDeviceCount %d is greater than class MaxDeviceCount %d", *claim.Spec.MaxDeviceCount, *class.Spec.MaxDeviceCount)
+		}
+		spec.MaxDeviceCount = copyPtr(claim.Spec.MaxDeviceCount)
+	}
+
+	if minCount < 1 {
+		return nil, fmt.Errorf("MinDeviceCount %d must be at least 1", minCount)
+	}
+
+	if spec.MaxDeviceCount != nil && minCount > *spec.MaxDeviceCount {
+		return nil, fmt.Errorf("MinDeviceCount %d is greater than MaxDeviceCount %d", minCount, *spec.MaxDeviceCount)
+	}
+
+	seen := make(map[string]bool)
+	for _, attrs := range [][]string{class.Spec.MatchAttributes, claim.Spec.MatchAttributes} {
+		for _, a := range attrs {
+			if !seen[a] {
+				seen[a] = true
+				spec.MatchAttributes = append(spec.MatchAttributes, a)
+			}
+		}
+	}
+
+	return effective, nil
+}
+
+// andConstraints combines the CEL expressions, so that both must be true. It
+// returns nil if neither is set.
+func andConstraints(a, b *string) *string {
+	switch {
+	case a == nil || *a == "":
+		return copyPtr(b)
+	case b == nil || *b == "":
+		return copyPtr(a)
+	}
+
+	result := fmt.Sprintf("(%s) && (%s)", *a, *b)
+	return &result
+}
+
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+
+	v := *p
+	return &v
+}
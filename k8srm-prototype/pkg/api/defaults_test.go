@@ -0,0 +1,136 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptr[T any](val T) *T {
+	var v T = val
+	return &v
+}
+
+func TestEffectiveClaim(t *testing.T) {
+	testCases := map[string]struct {
+		claimSpec DeviceClaimSpec
+		classSpec DeviceClassSpec
+		expected  DeviceClaimSpec
+		expectErr string
+	}{
+		"defaults": {
+			claimSpec: DeviceClaimSpec{DeviceClass: "gpu"},
+			classSpec: DeviceClassSpec{DeviceType: "gpu"},
+			expected: DeviceClaimSpec{
+				DeviceClass:    "gpu",
+				MinDeviceCount: ptr(1),
+			},
+		},
+		"class values": {
+			claimSpec: DeviceClaimSpec{DeviceClass: "gpu"},
+			classSpec: DeviceClassSpec{
+				DeviceType:      "gpu",
+				Driver:          ptr("example.com-foozer"),
+				Constraints:     ptr("device.model == 'foozer-1000'"),
+				MinDeviceCount:  ptr(2),
+				MaxDeviceCount:  ptr(4),
+				MatchAttributes: []string{"numa"},
+			},
+			expected: DeviceClaimSpec{
+				DeviceClass:     "gpu",
+				Driver:          ptr("example.com-foozer"),
+				Constraints:     ptr("device.model == 'foozer-1000'"),
+				MinDeviceCount:  ptr(2),
+				MaxDeviceCount:  ptr(4),
+				MatchAttributes: []string{"numa"},
+			},
+		},
+		"claim overrides and merges": {
+			claimSpec: DeviceClaimSpec{
+				DeviceClass:     "gpu",
+				Driver:          ptr("example.com-foozer"),
+				Constraints:     ptr("device.memory >= quantity('40Gi')"),
+				MinDeviceCount:  ptr(3),
+				MaxDeviceCount:  ptr(3),
+				MatchAttributes: []string{"model", "numa"},
+			},
+			classSpec: DeviceClassSpec{
+				DeviceType:      "gpu",
+				Driver:          ptr("example.com-foozer"),
+				Constraints:     ptr("device.model == 'foozer-1000'"),
+				MinDeviceCount:  ptr(2),
+				MaxDeviceCount:  ptr(4),
+				MatchAttributes: []string{"numa"},
+			},
+			expected: DeviceClaimSpec{
+				DeviceClass:     "gpu",
+				Driver:          ptr("example.com-foozer"),
+				Constraints:     ptr("(device.model == 'foozer-1000') && (device.memory >= quantity('40Gi'))"),
+				MinDeviceCount:  ptr(3),
+				MaxDeviceCount:  ptr(3),
+				MatchAttributes: []string{"numa", "model"},
+			},
+		},
+		"claim driver without class driver": {
+			claimSpec: DeviceClaimSpec{DeviceClass: "gpu", Driver: ptr("example.com-barzer")},
+			classSpec: DeviceClassSpec{DeviceType: "gpu"},
+			expected: DeviceClaimSpec{
+				DeviceClass:    "gpu",
+				Driver:         ptr("example.com-barzer"),
+				MinDeviceCount: ptr(1),
+			},
+		},
+		"driver mismatch": {
+			claimSpec: DeviceClaimSpec{DeviceClass: "gpu", Driver: ptr("example.com-barzer")},
+			classSpec: DeviceClassSpec{DeviceType: "gpu", Driver: ptr("example.com-foozer")},
+			expectErr: `claim driver "example.com-barzer" does not match class driver "example.com-foozer"`,
+		},
+		"claim min below class min": {
+			claimSpec: DeviceClaimSpec{DeviceClass: "gpu", MinDeviceCount: ptr(1)},
+			classSpec: DeviceClassSpec{DeviceType: "gpu", MinDeviceCount: ptr(2)},
+			expectErr: "claim MinDeviceCount 1 is less than class MinDeviceCount 2",
+		},
+		"claim max above class max": {
+			claimSpec: DeviceClaimSpec{DeviceClass: "gpu", MaxDeviceCount: ptr(8)},
+			classSpec: DeviceClassSpec{DeviceType: "gpu", MaxDeviceCount: ptr(4)},
+			expectErr: "claim MaxDeviceCount 8 is greater than class MaxDeviceCount 4",
+		},
+		"default min above max": {
+			claimSpec: DeviceClaimSpec{DeviceClass: "gpu", MaxDeviceCount: ptr(0)},
+			classSpec: DeviceClassSpec{DeviceType: "gpu"},
+			expectErr: "MinDeviceCount 1 is greater than MaxDeviceCount 0",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			claim := &DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default"},
+				Spec:       tc.claimSpec,
+			}
+			class := &DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+				Spec:       tc.classSpec,
+			}
+
+			effective, err := EffectiveClaim(claim, class)
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, claim.ObjectMeta, effective.ObjectMeta)
+			require.Equal(t, tc.expected, effective.Spec)
+			require.Equal(t, tc.claimSpec, claim.Spec, "claim was modified")
+		})
+	}
+}
+
+func TestEffectiveClaimMissingClass(t *testing.T) {
+	claim := &DeviceClaim{Spec: DeviceClaimSpec{DeviceClass: "gpu"}}
+	_, err := EffectiveClaim(claim, nil)
+	require.EqualError(t, err, `device class "gpu" not found`)
+}
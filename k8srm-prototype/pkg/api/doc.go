@@ -0,0 +1,7 @@
+// Package api contains the types of the devmgmtproto.k8s.io/v1alpha1 API
+// group, along with helpers for working with them.
+//
+// +k8s:deepcopy-gen=package
+// +groupName=devmgmtproto.k8s.io
+// +versionName=v1alpha1
+package api
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// PodDeviceClaim is the prototype of the proposed PodSpec extension for
+// device claims. A Pod lists these in `spec.deviceClaims`, much like volumes,
+// and containers refer to them by name. Since the field does not exist in the
+// real PodSpec, consumers must read it out of the unstructured Pod.
+type PodDeviceClaim struct {
+	// Name is used to refer to this claim from the containers in the Pod.
+	// +required
+	Name string `json:"name"`
+
+	// Exactly one of these must be populated
+
+	// DeviceClaimSpec is an embedded claim. A DeviceClaim with this spec
+	// is created for the Pod, and shares its lifecycle.
+	DeviceClaimSpec *DeviceClaimSpec `json:"claim,omitempty"`
+
+	// DeviceClaimName refers to an existing DeviceClaim in the same
+	// namespace as the Pod.
+	DeviceClaimName *string `json:"claimName,omitempty"`
+
+	// DeviceClaimTemplateName refers to a template in the same namespace as
+	// the Pod, from which a DeviceClaim is generated for the Pod.
+	DeviceClaimTemplateName *string `json:"claimTemplateName,omitempty"`
+}
+
+// podClaimNameMaxLength is the maximum length of an object name, which is a
+// DNS subdomain.
+const podClaimNameMaxLength = 253
+
+// podClaimNameHashLength is the number of hex digits of the hash suffix.
+const podClaimNameHashLength = 10
+
+// PodClaimName returns the name of the claim that is generated for a Pod from
+// an embedded claim or a claim template. The name is deterministic, so that
+// the claim can be found from the Pod. It is "<pod>-<claim>-<hash>", where the
+// hash is taken over both names, so that pod "a-b" with claim "c" and pod "a"
+// with claim "b-c" get different claims. The "<pod>-<claim>" prefix is
+// truncated to keep the name within 253 characters.
+func PodClaimName(podName, claimName string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s%d:%s", len(podName), podName, len(claimName), claimName)))
+	hash := hex.EncodeToString(sum[:])[:podClaimNameHashLength]
+
+	prefix := podName + "-" + claimName
+	if maxPrefix := podClaimNameMaxLength - len(hash) - 1; len(prefix) > maxPrefix {
+		prefix = strings.TrimRight(prefix[:maxPrefix], "-.")
+	}
+	return prefix + "-" + hash
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodClaimName(t *testing.T) {
+	testCases := map[string]struct {
+		podName   string
+		claimName string
+		prefix    string
+	}{
+		"short names": {
+			podName:   "my-pod",
+			claimName: "gpu",
+			prefix:    "my-pod-gpu-",
+		},
+		"long names": {
+			podName:   strings.Repeat("p", 200),
+			claimName: strings.Repeat("c", 100),
+			prefix:    strings.Repeat("p", 200) + "-" + strings.Repeat("c", 41) + "-",
+		},
+		"truncated at a dash": {
+			podName:   strings.Repeat("p", 241),
+			claimName: "gpu",
+			prefix:    strings.Repeat("p", 241) + "-",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			name := PodClaimName(tc.podName, tc.claimName)
+			require.LessOrEqual(t, len(name), 253)
+			require.True(t, strings.HasPrefix(name, tc.prefix), "name %q does not start with %q", name, tc.prefix)
+			require.Len(t, strings.TrimPrefix(name, tc.prefix), 10)
+			require.Equal(t, name, PodClaimName(tc.podName, tc.claimName))
+		})
+	}
+}
+
+func TestPodClaimNameCollision(t *testing.T) {
+	require.NotEqual(t, PodClaimName("a-b", "c"), PodClaimName("a", "b-c"))
+
+	// Names that only differ after the truncation point still get
+	// different claims.
+	long := strings.Repeat("p", 250)
+	require.NotEqual(t, PodClaimName(long, "gpu"), PodClaimName(long, "nic"))
+}
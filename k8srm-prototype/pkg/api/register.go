@@ -0,0 +1,53 @@
+package api
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the name of the API group of the device management types.
+const GroupName = "devmgmtproto.k8s.io"
+
+// SchemeGroupVersion is the group and version of the device management types.
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+var (
+	// SchemeBuilder registers the device management types with a scheme.
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+
+	// AddToScheme adds the device management types to the scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+// Kind takes an unqualified kind and returns a group-qualified GroupKind.
+func Kind(kind string) schema.GroupKind {
+	return SchemeGroupVersion.WithKind(kind).GroupKind()
+}
+
+// Resource takes an unqualified resource and returns a group-qualified
+// GroupResource.
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&DeviceDriver{},
+		&DeviceDriverList{},
+		&DeviceClass{},
+		&DeviceClassList{},
+		&DeviceClaim{},
+		&DeviceClaimList{},
+		&DevicePrivilegedClaim{},
+		&DevicePrivilegedClaimList{},
+		&DeviceSetClaim{},
+		&DeviceSetClaimList{},
+		&DeviceClaimTemplate{},
+		&DeviceClaimTemplateList{},
+		&DevicePool{},
+		&DevicePoolList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestAddToScheme(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, AddToScheme(scheme))
+
+	// Every kind that DecodeStrict knows about must be registered, along
+	// with its list.
+	for kind, newObject := range newObjectForKind {
+		obj, ok := newObject().(runtime.Object)
+		require.True(t, ok, "%s is not a runtime.Object", kind)
+
+		gvks, _, err := scheme.ObjectKinds(obj)
+		require.NoError(t, err)
+		require.Equal(t, SchemeGroupVersion.WithKind(kind), gvks[0])
+
+		require.True(t, scheme.Recognizes(SchemeGroupVersion.WithKind(kind+"List")), "%sList is not registered", kind)
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	claim := &DeviceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default"},
+		Spec: DeviceClaimSpec{
+			DeviceClass:     "gpu",
+			MinDeviceCount:  ptr(2),
+			MatchAttributes: []string{"numa"},
+		},
+		Status: DeviceClaimStatus{
+			Allocations: []DevicePoolAllocation{{DevicePoolName: "pool", DeviceCount: 2}},
+		},
+	}
+
+	copied := claim.DeepCopyObject().(*DeviceClaim)
+	require.Equal(t, claim, copied)
+
+	*copied.Spec.MinDeviceCount = 3
+	copied.Spec.MatchAttributes[0] = "model"
+	copied.Status.Allocations[0].DeviceCount = 1
+	require.Equal(t, 2, *claim.Spec.MinDeviceCount)
+	require.Equal(t, "numa", claim.Spec.MatchAttributes[0])
+	require.Equal(t, 2, claim.Status.Allocations[0].DeviceCount)
+}
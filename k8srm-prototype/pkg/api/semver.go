@@ -0,0 +1,37 @@
+package api
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Parse parses the semantic version. The major, minor, and patch versions are
+// all required, and may be followed by a prerelease and build metadata; for
+// example, "4.2.1-gen3+abc".
+func (s SemVer) Parse() (*version.Version, error) {
+	v, err := version.ParseSemantic(string(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid semantic version %q: %w", string(s), err)
+	}
+
+	return v, nil
+}
+
+// Validate returns an error if the SemVer is not a valid semantic version.
+func (s SemVer) Validate() error {
+	_, err := s.Parse()
+	return err
+}
+
+// Compare returns -1, 0, or 1 if s is less than, equal to, or greater than
+// other, following the semantic versioning precedence rules. In particular, a
+// prerelease version is less than the release, and build metadata is ignored.
+func (s SemVer) Compare(other SemVer) (int, error) {
+	v, err := s.Parse()
+	if err != nil {
+		return 0, err
+	}
+
+	return v.Compare(string(other))
+}
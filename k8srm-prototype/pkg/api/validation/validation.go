@@ -0,0 +1,474 @@
+// Package validation checks the device management API types, in the style of
+// the Kubernetes API validation. Each function returns a list of errors, with
+// the path of the offending field.
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/cel"
+
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var attributeTypes = sets.New(
+	api.AttributeTypeString,
+	api.AttributeTypeInt,
+	api.AttributeTypeQuantity,
+	api.AttributeTypeSemVer,
+)
+
+// ValidateDeviceDriver validates a DeviceDriver.
+func ValidateDeviceDriver(driver *api.DeviceDriver) field.ErrorList {
+	allErrs := apimachineryvalidation.ValidateObjectMeta(&driver.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
+
+	fldPath := field.NewPath("deviceTypes")
+	if len(driver.DeviceTypes) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "must register at least one device type"))
+	}
+	for i, dt := range driver.DeviceTypes {
+		allErrs = append(allErrs, validateDNSLabel(dt, fldPath.Index(i))...)
+	}
+
+	fldPath = field.NewPath("attributeSchema")
+	names := sets.New[string]()
+	for i, as := range driver.AttributeSchema {
+		idxPath := fldPath.Index(i)
+		allErrs = append(allErrs, validateAttributeName(as.Name, idxPath.Child("name"))...)
+		if names.Has(as.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), as.Name))
+		}
+		names.Insert(as.Name)
+
+		if !attributeTypes.Has(as.Type) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("type"), as.Type, sets.List(attributeTypes)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateDeviceClass validates a DeviceClass.
+func ValidateDeviceClass(class *api.DeviceClass) field.ErrorList {
+	allErrs := apimachineryvalidation.ValidateObjectMeta(&class.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
+	return append(allErrs, ValidateDeviceClassSpec(&class.Spec, field.NewPath("spec"))...)
+}
+
+// ValidateDeviceClassSpec validates the spec of a DeviceClass.
+func ValidateDeviceClassSpec(spec *api.DeviceClassSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.DeviceType == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("deviceType"), ""))
+	} else {
+		allErrs = append(allErrs, validateDNSLabel(spec.DeviceType, fldPath.Child("deviceType"))...)
+	}
+
+	if spec.Driver != nil {
+		allErrs = append(allErrs, validateDriverName(*spec.Driver, fldPath.Child("driver"))...)
+	}
+
+	allErrs = append(allErrs, validateConstraints(spec.Constraints, fldPath.Child("constraints"))...)
+	allErrs = append(allErrs, validateDeviceCounts(spec.MinDeviceCount, spec.MaxDeviceCount, fldPath)...)
+	allErrs = append(allErrs, validateMatchAttributes(spec.MatchAttributes, fldPath.Child("matchAttributes"))...)
+
+	for i := range spec.Configs {
+		allErrs = append(allErrs, validateDeviceClassConfigReference(&spec.Configs[i], fldPath.Child("configs").Index(i))...)
+	}
+
+	return allErrs
+}
+
+// ValidateDeviceClaim validates a DeviceClaim. If the class is not nil, the
+// claim is also checked for consistency with it.
+func ValidateDeviceClaim(claim *api.DeviceClaim, class *api.DeviceClass) field.ErrorList {
+	allErrs := apimachineryvalidation.ValidateObjectMeta(&claim.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
+	return append(allErrs, ValidateDeviceClaimSpec(&claim.Spec, class, field.NewPath("spec"))...)
+}
+
+// ValidateDeviceClaimSpec validates the spec of a DeviceClaim. If the class is
+// not nil, the claim driver and device counts are checked against it.
+func ValidateDeviceClaimSpec(spec *api.DeviceClaimSpec, class *api.DeviceClass, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.DeviceClass == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("deviceClass"), ""))
+	} else {
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(spec.DeviceClass, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("deviceClass"), spec.DeviceClass, msg))
+		}
+	}
+
+	if spec.Driver != nil {
+		allErrs = append(allErrs, validateDriverName(*spec.Driver, fldPath.Child("driver"))...)
+	}
+
+	allErrs = append(allErrs, validateConstraints(spec.Constraints, fldPath.Child("constraints"))...)
+	allErrs = append(allErrs, validateDeviceCounts(spec.MinDeviceCount, spec.MaxDeviceCount, fldPath)...)
+	allErrs = append(allErrs, validateMatchAttributes(spec.MatchAttributes, fldPath.Child("matchAttributes"))...)
+
+	for i := range spec.Configs {
+		allErrs = append(allErrs, validateDeviceConfigReference(&spec.Configs[i], fldPath.Child("configs").Index(i))...)
+	}
+
+	if class != nil {
+		allErrs = append(allErrs, validateClaimAgainstClass(spec, class, fldPath)...)
+	}
+
+	return allErrs
+}
+
+// validateClaimAgainstClass checks that the claim does not conflict with the
+// class it uses.
+func validateClaimAgainstClass(spec *api.DeviceClaimSpec, class *api.DeviceClass, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Driver != nil && class.Spec.Driver != nil && *spec.Driver != *class.Spec.Driver {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("driver"), *spec.Driver,
+			fmt.Sprintf("must match the driver of device class %q, %q", class.Name, *class.Spec.Driver)))
+	}
+
+	if spec.MinDeviceCount != nil && class.Spec.MinDeviceCount != nil && *spec.MinDeviceCount < *class.Spec.MinDeviceCount {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minDeviceCount"), *spec.MinDeviceCount,
+			fmt.Sprintf("must be greater than or equal to the minDeviceCount of device class %q, %d", class.Name, *class.Spec.MinDeviceCount)))
+	}
+
+	if spec.MaxDeviceCount != nil && class.Spec.MinDeviceCount != nil && *spec.MaxDeviceCount < *class.Spec.MinDeviceCount {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxDeviceCount"), *spec.MaxDeviceCount,
+			fmt.Sprintf("must be greater than or equal to the minDeviceCount of device class %q, %d", class.Name, *class.Spec.MinDeviceCount)))
+	}
+
+	if class.Spec.MaxDeviceCount != nil {
+		if spec.MinDeviceCount != nil && *spec.MinDeviceCount > *class.Spec.MaxDeviceCount {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("minDeviceCount"), *spec.MinDeviceCount,
+				fmt.Sprintf("must be less than or equal to the maxDeviceCount of device class %q, %d", class.Name, *class.Spec.MaxDeviceCount)))
+		}
+
+		if spec.MaxDeviceCount != nil && *spec.MaxDeviceCount > *class.Spec.MaxDeviceCount {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxDeviceCount"), *spec.MaxDeviceCount,
+				fmt.Sprintf("must be less than or equal to the maxDeviceCount of device class %q, %d", class.Name, *class.Spec.MaxDeviceCount)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateDevicePrivilegedClaim validates a DevicePrivilegedClaim.
+func ValidateDevicePrivilegedClaim(claim *api.DevicePrivilegedClaim) field.ErrorList {
+	allErrs := apimachineryvalidation.ValidateObjectMeta(&claim.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
+	return append(allErrs, ValidateDevicePrivilegedClaimSpec(&claim.Spec, field.NewPath("spec"))...)
+}
+
+// ValidateDevicePrivilegedClaimSpec validates the spec of a DevicePrivilegedClaim.
+func ValidateDevicePrivilegedClaimSpec(spec *api.DevicePrivilegedClaimSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Driver == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("driver"), ""))
+	} else {
+		allErrs = append(allErrs, validateDriverName(spec.Driver, fldPath.Child("driver"))...)
+	}
+
+	allErrs = append(allErrs, validateConstraints(spec.Constraints, fldPath.Child("constraints"))...)
+
+	for i := range spec.Configs {
+		allErrs = append(allErrs, validateDeviceConfigReference(&spec.Configs[i], fldPath.Child("configs").Index(i))...)
+	}
+
+	return allErrs
+}
+
+// ValidateDeviceSetClaim validates a DeviceSetClaim.
+func ValidateDeviceSetClaim(claim *api.DeviceSetClaim) field.ErrorList {
+	allErrs := apimachineryvalidation.ValidateObjectMeta(&claim.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
+	return append(allErrs, ValidateDeviceSetClaimSpec(&claim.Spec, field.NewPath("spec"))...)
+}
+
+// ValidateDeviceSetClaimSpec validates the spec of a DeviceSetClaim.
+func ValidateDeviceSetClaimSpec(spec *api.DeviceSetClaimSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateMatchAttributes(spec.MatchAttributes, fldPath.Child("matchAttributes"))...)
+
+	if len(spec.ClaimSpec) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("claimSpec"), "must contain at least one claim"))
+	}
+	for i := range spec.ClaimSpec {
+		allErrs = append(allErrs, ValidateDeviceClaimSpec(&spec.ClaimSpec[i], nil, fldPath.Child("claimSpec").Index(i))...)
+	}
+
+	return allErrs
+}
+
+// ValidateDeviceClaimTemplate validates a DeviceClaimTemplate.
+func ValidateDeviceClaimTemplate(template *api.DeviceClaimTemplate) field.ErrorList {
+	allErrs := apimachineryvalidation.ValidateObjectMeta(&template.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
+	return append(allErrs, ValidateDeviceClaimTemplateSpec(&template.Spec, field.NewPath("spec"))...)
+}
+
+// ValidateDeviceClaimTemplateSpec validates the spec of a DeviceClaimTemplate,
+// which must contain exactly one kind of claim.
+func ValidateDeviceClaimTemplateSpec(spec *api.DeviceClaimTemplateSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	var set []string
+	if spec.DeviceClaimSpec != nil {
+		set = append(set, "claimSpec")
+		allErrs = append(allErrs, ValidateDeviceClaimSpec(spec.DeviceClaimSpec, nil, fldPath.Child("claimSpec"))...)
+	}
+	if spec.DevicePrivilegedClaimSpec != nil {
+		set = append(set, "privilegedClaimSpec")
+		allErrs = append(allErrs, ValidateDevicePrivilegedClaimSpec(spec.DevicePrivilegedClaimSpec, fldPath.Child("privilegedClaimSpec"))...)
+	}
+	if spec.DeviceSetClaimSpec != nil {
+		set = append(set, "setClaimSpec")
+		allErrs = append(allErrs, ValidateDeviceSetClaimSpec(spec.DeviceSetClaimSpec, fldPath.Child("setClaimSpec"))...)
+	}
+
+	return append(allErrs, validateOneOf(set, []string{"claimSpec", "privilegedClaimSpec", "setClaimSpec"}, fldPath)...)
+}
+
+// ValidatePodDeviceClaim validates a device claim entry in a Pod.
+func ValidatePodDeviceClaim(claim *api.PodDeviceClaim, fldPath *field.Path) field.ErrorList {
+	allErrs := validateDNSLabel(claim.Name, fldPath.Child("name"))
+
+	var set []string
+	if claim.DeviceClaimSpec != nil {
+		set = append(set, "claim")
+		allErrs = append(allErrs, ValidateDeviceClaimSpec(claim.DeviceClaimSpec, nil, fldPath.Child("claim"))...)
+	}
+	if claim.DeviceClaimName != nil {
+		set = append(set, "claimName")
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(*claim.DeviceClaimName, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("claimName"), *claim.DeviceClaimName, msg))
+		}
+	}
+	if claim.DeviceClaimTemplateName != nil {
+		set = append(set, "claimTemplateName")
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(*claim.DeviceClaimTemplateName, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("claimTemplateName"), *claim.DeviceClaimTemplateName, msg))
+		}
+	}
+
+	return append(allErrs, validateOneOf(set, []string{"claim", "claimName", "claimTemplateName"}, fldPath)...)
+}
+
+// ValidateDevicePool validates a DevicePool.
+func ValidateDevicePool(pool *api.DevicePool) field.ErrorList {
+	allErrs := apimachineryvalidation.ValidateObjectMeta(&pool.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
+
+	fldPath := field.NewPath("spec")
+	spec := &pool.Spec
+
+	if spec.Driver == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("driver"), ""))
+	} else {
+		allErrs = append(allErrs, validateDriverName(spec.Driver, fldPath.Child("driver"))...)
+	}
+
+	if spec.DeviceCount < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("count"), spec.DeviceCount, "must be greater than or equal to 0"))
+	}
+
+	if spec.NodeName != nil && *spec.NodeName != "" {
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(*spec.NodeName, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeName"), *spec.NodeName, msg))
+		}
+
+		if spec.NodeSelector != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeSelector"), "may not be set for a pool with a nodeName"))
+		}
+
+		if len(spec.ReachabilityAttributes) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("reachabilityAttributes"), "may not be set for a pool with a nodeName"))
+		}
+	}
+
+	if spec.NodeSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(spec.NodeSelector, metav1validation.LabelSelectorValidationOptions{}, fldPath.Child("nodeSelector"))...)
+	}
+
+	allErrs = append(allErrs, validateMatchAttributes(spec.ReachabilityAttributes, fldPath.Child("reachabilityAttributes"))...)
+
+	names := sets.New[string]()
+	for i := range spec.Attributes {
+		idxPath := fldPath.Child("attributes").Index(i)
+		allErrs = append(allErrs, ValidateAttribute(&spec.Attributes[i], idxPath)...)
+		if names.Has(spec.Attributes[i].Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), spec.Attributes[i].Name))
+		}
+		names.Insert(spec.Attributes[i].Name)
+	}
+
+	return allErrs
+}
+
+// ValidateAttribute validates a device attribute, which must have exactly one
+// value.
+func ValidateAttribute(attr *api.Attribute, fldPath *field.Path) field.ErrorList {
+	allErrs := validateAttributeName(attr.Name, fldPath.Child("name"))
+
+	var set []string
+	if attr.StringValue != nil {
+		set = append(set, "stringValue")
+	}
+	if attr.IntValue != nil {
+		set = append(set, "intValue")
+	}
+	if attr.QuantityValue != nil {
+		set = append(set, "quantityValue")
+	}
+	if attr.SemVerValue != nil {
+		set = append(set, "semVerValue")
+		if err := attr.SemVerValue.Validate(); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("semVerValue"), *attr.SemVerValue, err.Error()))
+		}
+	}
+
+	return append(allErrs, validateOneOf(set, []string{"stringValue", "intValue", "quantityValue", "semVerValue"}, fldPath)...)
+}
+
+// validateOneOf reports an error unless exactly one of the fields was set.
+func validateOneOf(set, fields []string, fldPath *field.Path) field.ErrorList {
+	switch len(set) {
+	case 0:
+		return field.ErrorList{field.Required(fldPath, fmt.Sprintf("must specify exactly one of: %s", strings.Join(fields, ", ")))}
+	case 1:
+		return nil
+	}
+
+	return field.ErrorList{field.Forbidden(fldPath.Child(set[1]), fmt.Sprintf("may not be specified together with %s", set[0]))}
+}
+
+func validateDNSLabel(value string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, msg := range validation.IsDNS1123Label(value) {
+		allErrs = append(allErrs, field.Invalid(fldPath, value, msg))
+	}
+
+	return allErrs
+}
+
+// validateDriverName checks that the driver name could be the name of a
+// DeviceDriver.
+func validateDriverName(name string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(name, false) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+
+	return allErrs
+}
+
+// validateAttributeName checks that the attribute may be used in CEL
+// expressions, as a field of the device.
+func validateAttributeName(name string, fldPath *field.Path) field.ErrorList {
+	if name == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+
+	var allErrs field.ErrorList
+	for _, msg := range validation.IsCIdentifier(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+
+	return allErrs
+}
+
+func validateMatchAttributes(names []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.New[string]()
+	for i, name := range names {
+		allErrs = append(allErrs, validateAttributeName(name, fldPath.Index(i))...)
+		if seen.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), name))
+		}
+		seen.Insert(name)
+	}
+
+	return allErrs
+}
+
+// validateConstraints checks that the constraints compile. Types cannot be
+// checked without the attribute schemas of the drivers; see
+// schedule.CheckClassConstraints and schedule.CheckClaimConstraints.
+func validateConstraints(constraints *string, fldPath *field.Path) field.ErrorList {
+	if constraints == nil || *constraints == "" {
+		return nil
+	}
+
+	if err := cel.CompileConstraints(*constraints, nil); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, *constraints, err.Error())}
+	}
+
+	return nil
+}
+
+func validateDeviceCounts(minCount, maxCount *int, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if minCount != nil && *minCount < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minDeviceCount"), *minCount, "must be greater than or equal to 1"))
+	}
+
+	if maxCount != nil && *maxCount < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxDeviceCount"), *maxCount, "must be greater than or equal to 1"))
+	}
+
+	if minCount != nil && maxCount != nil && *minCount > *maxCount {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minDeviceCount"), *minCount, "must be less than or equal to maxDeviceCount"))
+	}
+
+	return allErrs
+}
+
+// validateDeviceClassConfigReference validates a config reference in a class.
+// Since classes are cluster scoped, these must specify the namespace.
+func validateDeviceClassConfigReference(ref *api.DeviceClassConfigReference, fldPath *field.Path) field.ErrorList {
+	allErrs := validateObjectReference(ref.APIVersion, ref.Kind, ref.Name, fldPath)
+
+	if ref.Namespace == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("namespace"), "config references in a class must specify the namespace"))
+	} else {
+		for _, msg := range apimachineryvalidation.ValidateNamespaceName(ref.Namespace, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), ref.Namespace, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateDeviceConfigReference validates a config reference in a claim. These
+// have no namespace, since they may only refer to objects in the namespace of
+// the claim.
+func validateDeviceConfigReference(ref *api.DeviceConfigReference, fldPath *field.Path) field.ErrorList {
+	return validateObjectReference(ref.APIVersion, ref.Kind, ref.Name, fldPath)
+}
+
+func validateObjectReference(apiVersion, kind, name string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if apiVersion == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("apiVersion"), ""))
+	}
+
+	if kind == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("kind"), ""))
+	}
+
+	if name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), ""))
+	} else {
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(name, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), name, msg))
+		}
+	}
+
+	return allErrs
+}
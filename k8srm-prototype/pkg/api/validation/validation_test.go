@@ -0,0 +1,477 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func ptr[T any](val T) *T {
+	var v T = val
+	return &v
+}
+
+// requireErrors checks that the errors are for exactly the listed fields, with
+// the listed types, in order.
+func requireErrors(t *testing.T, expected []string, errs field.ErrorList) {
+	var actual []string
+	for _, err := range errs {
+		actual = append(actual, string(err.Type)+": "+err.Field)
+	}
+	require.Equal(t, expected, actual, errs.ToAggregate())
+}
+
+func TestValidateDeviceClass(t *testing.T) {
+	testCases := map[string]struct {
+		class     api.DeviceClass
+		expectErr []string
+	}{
+		"valid": {
+			class: api.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "example.com-foozer"},
+				Spec: api.DeviceClassSpec{
+					DeviceType:      "gpu",
+					Driver:          ptr("example.com-foozer"),
+					Constraints:     ptr("device.model == 'foozer-1000'"),
+					MinDeviceCount:  ptr(1),
+					MaxDeviceCount:  ptr(4),
+					MatchAttributes: []string{"numa"},
+					Configs: []api.DeviceClassConfigReference{
+						{APIVersion: "example.com/v1", Kind: "FoozerConfig", Namespace: "kube-system", Name: "default"},
+					},
+				},
+			},
+		},
+		"missing name and device type": {
+			class: api.DeviceClass{},
+			expectErr: []string{
+				"FieldValueRequired: metadata.name",
+				"FieldValueRequired: spec.deviceType",
+			},
+		},
+		"namespaced": {
+			class: api.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "default"},
+				Spec:       api.DeviceClassSpec{DeviceType: "gpu"},
+			},
+			expectErr: []string{
+				"FieldValueForbidden: metadata.namespace",
+			},
+		},
+		"min greater than max": {
+			class: api.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+				Spec: api.DeviceClassSpec{
+					DeviceType:     "gpu",
+					MinDeviceCount: ptr(4),
+					MaxDeviceCount: ptr(2),
+				},
+			},
+			expectErr: []string{
+				"FieldValueInvalid: spec.minDeviceCount",
+			},
+		},
+		"zero counts": {
+			class: api.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+				Spec: api.DeviceClassSpec{
+					DeviceType:     "gpu",
+					MinDeviceCount: ptr(0),
+					MaxDeviceCount: ptr(0),
+				},
+			},
+			expectErr: []string{
+				"FieldValueInvalid: spec.minDeviceCount",
+				"FieldValueInvalid: spec.maxDeviceCount",
+			},
+		},
+		"bad constraints": {
+			class: api.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+				Spec: api.DeviceClassSpec{
+					DeviceType:  "gpu",
+					Constraints: ptr("device.model =="),
+				},
+			},
+			expectErr: []string{
+				"FieldValueInvalid: spec.constraints",
+			},
+		},
+		"bad match attributes": {
+			class: api.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+				Spec: api.DeviceClassSpec{
+					DeviceType:      "gpu",
+					MatchAttributes: []string{"device.model", "numa", "numa"},
+				},
+			},
+			expectErr: []string{
+				"FieldValueInvalid: spec.matchAttributes[0]",
+				"FieldValueDuplicate: spec.matchAttributes[2]",
+			},
+		},
+		"config without namespace": {
+			class: api.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+				Spec: api.DeviceClassSpec{
+					DeviceType: "gpu",
+					Configs: []api.DeviceClassConfigReference{
+						{APIVersion: "example.com/v1", Kind: "FoozerConfig", Name: "default"},
+					},
+				},
+			},
+			expectErr: []string{
+				"FieldValueRequired: spec.configs[0].namespace",
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			requireErrors(t, tc.expectErr, ValidateDeviceClass(&tc.class))
+		})
+	}
+}
+
+func TestValidateDeviceClaim(t *testing.T) {
+	class := &api.DeviceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "foozer-up-to-four"},
+		Spec: api.DeviceClassSpec{
+			DeviceType:     "gpu",
+			Driver:         ptr("example.com-foozer"),
+			MinDeviceCount: ptr(2),
+			MaxDeviceCount: ptr(4),
+		},
+	}
+
+	testCases := map[string]struct {
+		claim     api.DeviceClaim
+		class     *api.DeviceClass
+		expectErr []string
+	}{
+		"valid": {
+			claim: api.DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclaim", Namespace: "default"},
+				Spec: api.DeviceClaimSpec{
+					DeviceClass:    "foozer-up-to-four",
+					Driver:         ptr("example.com-foozer"),
+					MinDeviceCount: ptr(2),
+					MaxDeviceCount: ptr(3),
+					Configs: []api.DeviceConfigReference{
+						{APIVersion: "example.com/v1", Kind: "FoozerConfig", Name: "mine"},
+					},
+				},
+			},
+			class: class,
+		},
+		"missing namespace and class": {
+			claim: api.DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclaim"},
+			},
+			expectErr: []string{
+				"FieldValueRequired: metadata.namespace",
+				"FieldValueRequired: spec.deviceClass",
+			},
+		},
+		"driver does not match class": {
+			claim: api.DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclaim", Namespace: "default"},
+				Spec: api.DeviceClaimSpec{
+					DeviceClass: "foozer-up-to-four",
+					Driver:      ptr("example.com-barzer"),
+				},
+			},
+			class: class,
+			expectErr: []string{
+				"FieldValueInvalid: spec.driver",
+			},
+		},
+		"counts outside the class range": {
+			claim: api.DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclaim", Namespace: "default"},
+				Spec: api.DeviceClaimSpec{
+					DeviceClass:    "foozer-up-to-four",
+					MinDeviceCount: ptr(1),
+					MaxDeviceCount: ptr(8),
+				},
+			},
+			class: class,
+			expectErr: []string{
+				"FieldValueInvalid: spec.minDeviceCount",
+				"FieldValueInvalid: spec.maxDeviceCount",
+			},
+		},
+		"maximum below the class minimum": {
+			claim: api.DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclaim", Namespace: "default"},
+				Spec: api.DeviceClaimSpec{
+					DeviceClass:    "foozer-up-to-four",
+					MaxDeviceCount: ptr(1),
+				},
+			},
+			class: class,
+			expectErr: []string{
+				"FieldValueInvalid: spec.maxDeviceCount",
+			},
+		},
+		"counts not checked without the class": {
+			claim: api.DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclaim", Namespace: "default"},
+				Spec: api.DeviceClaimSpec{
+					DeviceClass:    "foozer-up-to-four",
+					MinDeviceCount: ptr(1),
+					MaxDeviceCount: ptr(8),
+				},
+			},
+		},
+		"config missing fields": {
+			claim: api.DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclaim", Namespace: "default"},
+				Spec: api.DeviceClaimSpec{
+					DeviceClass: "foozer-up-to-four",
+					Configs: []api.DeviceConfigReference{
+						{Name: "Not_A_Name"},
+					},
+				},
+			},
+			expectErr: []string{
+				"FieldValueRequired: spec.configs[0].apiVersion",
+				"FieldValueRequired: spec.configs[0].kind",
+				"FieldValueInvalid: spec.configs[0].name",
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			requireErrors(t, tc.expectErr, ValidateDeviceClaim(&tc.claim, tc.class))
+		})
+	}
+}
+
+func TestValidateDeviceClaimTemplateSpec(t *testing.T) {
+	claimSpec := &api.DeviceClaimSpec{DeviceClass: "gpu"}
+	privilegedSpec := &api.DevicePrivilegedClaimSpec{Driver: "example.com-foozer"}
+	setSpec := &api.DeviceSetClaimSpec{
+		MatchAttributes: []string{"numa"},
+		ClaimSpec:       []api.DeviceClaimSpec{*claimSpec, {}},
+	}
+
+	testCases := map[string]struct {
+		spec      api.DeviceClaimTemplateSpec
+		expectErr []string
+	}{
+		"claim": {
+			spec: api.DeviceClaimTemplateSpec{DeviceClaimSpec: claimSpec},
+		},
+		"privileged claim": {
+			spec: api.DeviceClaimTemplateSpec{DevicePrivilegedClaimSpec: privilegedSpec},
+		},
+		"set claim with an invalid member": {
+			spec: api.DeviceClaimTemplateSpec{DeviceSetClaimSpec: setSpec},
+			expectErr: []string{
+				"FieldValueRequired: spec.setClaimSpec.claimSpec[1].deviceClass",
+			},
+		},
+		"none": {
+			expectErr: []string{
+				"FieldValueRequired: spec",
+			},
+		},
+		"more than one": {
+			spec: api.DeviceClaimTemplateSpec{
+				DeviceClaimSpec:           claimSpec,
+				DevicePrivilegedClaimSpec: privilegedSpec,
+			},
+			expectErr: []string{
+				"FieldValueForbidden: spec.privilegedClaimSpec",
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			requireErrors(t, tc.expectErr, ValidateDeviceClaimTemplateSpec(&tc.spec, field.NewPath("spec")))
+		})
+	}
+}
+
+func TestValidateAttribute(t *testing.T) {
+	testCases := map[string]struct {
+		attr      api.Attribute
+		expectErr []string
+	}{
+		"string": {
+			attr: api.Attribute{Name: "model", StringValue: ptr("foozer-1000")},
+		},
+		"quantity": {
+			attr: api.Attribute{Name: "memory", QuantityValue: ptr(resource.MustParse("40Gi"))},
+		},
+		"semver": {
+			attr: api.Attribute{Name: "firmwareVersion", SemVerValue: ptr(api.SemVer("4.2.1-gen3"))},
+		},
+		"invalid semver": {
+			attr: api.Attribute{Name: "firmwareVersion", SemVerValue: ptr(api.SemVer("4.2"))},
+			expectErr: []string{
+				"FieldValueInvalid: attr.semVerValue",
+			},
+		},
+		"invalid name": {
+			attr: api.Attribute{Name: "firmware-version", StringValue: ptr("4.2.1")},
+			expectErr: []string{
+				"FieldValueInvalid: attr.name",
+			},
+		},
+		"no value": {
+			attr: api.Attribute{Name: "model"},
+			expectErr: []string{
+				"FieldValueRequired: attr",
+			},
+		},
+		"two values": {
+			attr: api.Attribute{Name: "numa", StringValue: ptr("0"), IntValue: ptr(0)},
+			expectErr: []string{
+				"FieldValueForbidden: attr.intValue",
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			requireErrors(t, tc.expectErr, ValidateAttribute(&tc.attr, field.NewPath("attr")))
+		})
+	}
+}
+
+func TestValidatePodDeviceClaim(t *testing.T) {
+	testCases := map[string]struct {
+		claim     api.PodDeviceClaim
+		expectErr []string
+	}{
+		"embedded claim": {
+			claim: api.PodDeviceClaim{
+				Name:            "gpu",
+				DeviceClaimSpec: &api.DeviceClaimSpec{DeviceClass: "gpu"},
+			},
+		},
+		"name is not a DNS label": {
+			claim: api.PodDeviceClaim{
+				Name:            "my.gpu",
+				DeviceClaimName: ptr("my-claim"),
+			},
+			expectErr: []string{
+				"FieldValueInvalid: deviceClaims[0].name",
+			},
+		},
+		"both claim name and template": {
+			claim: api.PodDeviceClaim{
+				Name:                    "gpu",
+				DeviceClaimName:         ptr("my-claim"),
+				DeviceClaimTemplateName: ptr("my-template"),
+			},
+			expectErr: []string{
+				"FieldValueForbidden: deviceClaims[0].claimTemplateName",
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			requireErrors(t, tc.expectErr, ValidatePodDeviceClaim(&tc.claim, field.NewPath("deviceClaims").Index(0)))
+		})
+	}
+}
+
+func TestValidateDevicePool(t *testing.T) {
+	testCases := map[string]struct {
+		spec      api.DevicePoolSpec
+		expectErr []string
+	}{
+		"local pool": {
+			spec: api.DevicePoolSpec{
+				NodeName:    ptr("node-00"),
+				Driver:      "example.com-foozer",
+				DeviceCount: 2,
+				Attributes: []api.Attribute{
+					{Name: "numa", StringValue: ptr("0")},
+				},
+			},
+		},
+		"network pool": {
+			spec: api.DevicePoolSpec{
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"rack": "a"},
+				},
+				Driver:      "example.com-barzer",
+				DeviceCount: 4,
+			},
+		},
+		"local pool with a node selector": {
+			spec: api.DevicePoolSpec{
+				NodeName: ptr("node-00"),
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"rack": "a"},
+				},
+				ReachabilityAttributes: []string{"rack"},
+				Driver:                 "example.com-barzer",
+			},
+			expectErr: []string{
+				"FieldValueForbidden: spec.nodeSelector",
+				"FieldValueForbidden: spec.reachabilityAttributes",
+			},
+		},
+		"missing driver and negative count": {
+			spec: api.DevicePoolSpec{
+				DeviceCount: -1,
+			},
+			expectErr: []string{
+				"FieldValueRequired: spec.driver",
+				"FieldValueInvalid: spec.count",
+			},
+		},
+		"duplicate attributes": {
+			spec: api.DevicePoolSpec{
+				Driver: "example.com-foozer",
+				Attributes: []api.Attribute{
+					{Name: "numa", StringValue: ptr("0")},
+					{Name: "numa", StringValue: ptr("1")},
+				},
+			},
+			expectErr: []string{
+				"FieldValueDuplicate: spec.attributes[1].name",
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			pool := api.DevicePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "mypool"},
+				Spec:       tc.spec,
+			}
+			requireErrors(t, tc.expectErr, ValidateDevicePool(&pool))
+		})
+	}
+}
+
+func TestValidateDeviceDriver(t *testing.T) {
+	driver := api.DeviceDriver{
+		ObjectMeta:  metav1.ObjectMeta{Name: "example.com-foozer"},
+		DeviceTypes: []string{"gpu", "Not A Type"},
+		AttributeSchema: []api.AttributeSchema{
+			{Name: "model", Type: api.AttributeTypeString},
+			{Name: "model", Type: "Float"},
+		},
+	}
+
+	requireErrors(t, []string{
+		"FieldValueInvalid: deviceTypes[1]",
+		"FieldValueDuplicate: attributeSchema[1].name",
+		"FieldValueNotSupported: attributeSchema[1].type",
+	}, ValidateDeviceDriver(&driver))
+}
@@ -0,0 +1,948 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package api
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Attribute) DeepCopyInto(out *Attribute) {
+	*out = *in
+	if in.StringValue != nil {
+		in, out := &in.StringValue, &out.StringValue
+		*out = new(string)
+		**out = **in
+	}
+	if in.IntValue != nil {
+		in, out := &in.IntValue, &out.IntValue
+		*out = new(int)
+		**out = **in
+	}
+	if in.QuantityValue != nil {
+		in, out := &in.QuantityValue, &out.QuantityValue
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.SemVerValue != nil {
+		in, out := &in.SemVerValue, &out.SemVerValue
+		*out = new(SemVer)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Attribute.
+func (in *Attribute) DeepCopy() *Attribute {
+	if in == nil {
+		return nil
+	}
+	out := new(Attribute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AttributeSchema) DeepCopyInto(out *AttributeSchema) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttributeSchema.
+func (in *AttributeSchema) DeepCopy() *AttributeSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(AttributeSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClaim) DeepCopyInto(out *DeviceClaim) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClaim.
+func (in *DeviceClaim) DeepCopy() *DeviceClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceClaim) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClaimList) DeepCopyInto(out *DeviceClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClaimList.
+func (in *DeviceClaimList) DeepCopy() *DeviceClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClaimSpec) DeepCopyInto(out *DeviceClaimSpec) {
+	*out = *in
+	if in.Driver != nil {
+		in, out := &in.Driver, &out.Driver
+		*out = new(string)
+		**out = **in
+	}
+	if in.Constraints != nil {
+		in, out := &in.Constraints, &out.Constraints
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinDeviceCount != nil {
+		in, out := &in.MinDeviceCount, &out.MinDeviceCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxDeviceCount != nil {
+		in, out := &in.MaxDeviceCount, &out.MaxDeviceCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.MatchAttributes != nil {
+		in, out := &in.MatchAttributes, &out.MatchAttributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Configs != nil {
+		in, out := &in.Configs, &out.Configs
+		*out = make([]DeviceConfigReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClaimSpec.
+func (in *DeviceClaimSpec) DeepCopy() *DeviceClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClaimStatus) DeepCopyInto(out *DeviceClaimStatus) {
+	*out = *in
+	if in.ClassConfigs != nil {
+		in, out := &in.ClassConfigs, &out.ClassConfigs
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClaimConfigs != nil {
+		in, out := &in.ClaimConfigs, &out.ClaimConfigs
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Allocations != nil {
+		in, out := &in.Allocations, &out.Allocations
+		*out = make([]DevicePoolAllocation, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodNames != nil {
+		in, out := &in.PodNames, &out.PodNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClaimStatus.
+func (in *DeviceClaimStatus) DeepCopy() *DeviceClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClaimTemplate) DeepCopyInto(out *DeviceClaimTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClaimTemplate.
+func (in *DeviceClaimTemplate) DeepCopy() *DeviceClaimTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClaimTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceClaimTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClaimTemplateList) DeepCopyInto(out *DeviceClaimTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceClaimTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClaimTemplateList.
+func (in *DeviceClaimTemplateList) DeepCopy() *DeviceClaimTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClaimTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceClaimTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClaimTemplateSpec) DeepCopyInto(out *DeviceClaimTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.DeviceClaimSpec != nil {
+		in, out := &in.DeviceClaimSpec, &out.DeviceClaimSpec
+		*out = new(DeviceClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DevicePrivilegedClaimSpec != nil {
+		in, out := &in.DevicePrivilegedClaimSpec, &out.DevicePrivilegedClaimSpec
+		*out = new(DevicePrivilegedClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeviceSetClaimSpec != nil {
+		in, out := &in.DeviceSetClaimSpec, &out.DeviceSetClaimSpec
+		*out = new(DeviceSetClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClaimTemplateSpec.
+func (in *DeviceClaimTemplateSpec) DeepCopy() *DeviceClaimTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClaimTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClass) DeepCopyInto(out *DeviceClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClass.
+func (in *DeviceClass) DeepCopy() *DeviceClass {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassConfigReference) DeepCopyInto(out *DeviceClassConfigReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassConfigReference.
+func (in *DeviceClassConfigReference) DeepCopy() *DeviceClassConfigReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassConfigReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassList) DeepCopyInto(out *DeviceClassList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassList.
+func (in *DeviceClassList) DeepCopy() *DeviceClassList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceClassList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassSpec) DeepCopyInto(out *DeviceClassSpec) {
+	*out = *in
+	if in.Driver != nil {
+		in, out := &in.Driver, &out.Driver
+		*out = new(string)
+		**out = **in
+	}
+	if in.Constraints != nil {
+		in, out := &in.Constraints, &out.Constraints
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinDeviceCount != nil {
+		in, out := &in.MinDeviceCount, &out.MinDeviceCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxDeviceCount != nil {
+		in, out := &in.MaxDeviceCount, &out.MaxDeviceCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.MatchAttributes != nil {
+		in, out := &in.MatchAttributes, &out.MatchAttributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Configs != nil {
+		in, out := &in.Configs, &out.Configs
+		*out = make([]DeviceClassConfigReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassSpec.
+func (in *DeviceClassSpec) DeepCopy() *DeviceClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassStatus) DeepCopyInto(out *DeviceClassStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drivers != nil {
+		in, out := &in.Drivers, &out.Drivers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassStatus.
+func (in *DeviceClassStatus) DeepCopy() *DeviceClassStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceConfigReference) DeepCopyInto(out *DeviceConfigReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceConfigReference.
+func (in *DeviceConfigReference) DeepCopy() *DeviceConfigReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceConfigReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceDriver) DeepCopyInto(out *DeviceDriver) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.DeviceTypes != nil {
+		in, out := &in.DeviceTypes, &out.DeviceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AttributeSchema != nil {
+		in, out := &in.AttributeSchema, &out.AttributeSchema
+		*out = make([]AttributeSchema, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceDriver.
+func (in *DeviceDriver) DeepCopy() *DeviceDriver {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceDriver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceDriver) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceDriverList) DeepCopyInto(out *DeviceDriverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceDriver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceDriverList.
+func (in *DeviceDriverList) DeepCopy() *DeviceDriverList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceDriverList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceDriverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePool) DeepCopyInto(out *DevicePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePool.
+func (in *DevicePool) DeepCopy() *DevicePool {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevicePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePoolAllocation) DeepCopyInto(out *DevicePoolAllocation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePoolAllocation.
+func (in *DevicePoolAllocation) DeepCopy() *DevicePoolAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePoolAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePoolList) DeepCopyInto(out *DevicePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevicePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePoolList.
+func (in *DevicePoolList) DeepCopy() *DevicePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevicePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePoolSpec) DeepCopyInto(out *DevicePoolSpec) {
+	*out = *in
+	if in.NodeName != nil {
+		in, out := &in.NodeName, &out.NodeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReachabilityAttributes != nil {
+		in, out := &in.ReachabilityAttributes, &out.ReachabilityAttributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]Attribute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePoolSpec.
+func (in *DevicePoolSpec) DeepCopy() *DevicePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePoolStatus) DeepCopyInto(out *DevicePoolStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePoolStatus.
+func (in *DevicePoolStatus) DeepCopy() *DevicePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePrivilegedClaim) DeepCopyInto(out *DevicePrivilegedClaim) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePrivilegedClaim.
+func (in *DevicePrivilegedClaim) DeepCopy() *DevicePrivilegedClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePrivilegedClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevicePrivilegedClaim) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePrivilegedClaimList) DeepCopyInto(out *DevicePrivilegedClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevicePrivilegedClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePrivilegedClaimList.
+func (in *DevicePrivilegedClaimList) DeepCopy() *DevicePrivilegedClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePrivilegedClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevicePrivilegedClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePrivilegedClaimSpec) DeepCopyInto(out *DevicePrivilegedClaimSpec) {
+	*out = *in
+	if in.Constraints != nil {
+		in, out := &in.Constraints, &out.Constraints
+		*out = new(string)
+		**out = **in
+	}
+	if in.Configs != nil {
+		in, out := &in.Configs, &out.Configs
+		*out = make([]DeviceConfigReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePrivilegedClaimSpec.
+func (in *DevicePrivilegedClaimSpec) DeepCopy() *DevicePrivilegedClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePrivilegedClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePrivilegedClaimStatus) DeepCopyInto(out *DevicePrivilegedClaimStatus) {
+	*out = *in
+	if in.ClaimConfigs != nil {
+		in, out := &in.ClaimConfigs, &out.ClaimConfigs
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Allocations != nil {
+		in, out := &in.Allocations, &out.Allocations
+		*out = make([]DevicePoolAllocation, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodNames != nil {
+		in, out := &in.PodNames, &out.PodNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePrivilegedClaimStatus.
+func (in *DevicePrivilegedClaimStatus) DeepCopy() *DevicePrivilegedClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePrivilegedClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetClaim) DeepCopyInto(out *DeviceSetClaim) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetClaim.
+func (in *DeviceSetClaim) DeepCopy() *DeviceSetClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceSetClaim) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetClaimList) DeepCopyInto(out *DeviceSetClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceSetClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetClaimList.
+func (in *DeviceSetClaimList) DeepCopy() *DeviceSetClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceSetClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetClaimSpec) DeepCopyInto(out *DeviceSetClaimSpec) {
+	*out = *in
+	if in.MatchAttributes != nil {
+		in, out := &in.MatchAttributes, &out.MatchAttributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClaimSpec != nil {
+		in, out := &in.ClaimSpec, &out.ClaimSpec
+		*out = make([]DeviceClaimSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetClaimSpec.
+func (in *DeviceSetClaimSpec) DeepCopy() *DeviceSetClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetClaimStatus) DeepCopyInto(out *DeviceSetClaimStatus) {
+	*out = *in
+	if in.ClaimStatus != nil {
+		in, out := &in.ClaimStatus, &out.ClaimStatus
+		*out = make([]DeviceClaimStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetClaimStatus.
+func (in *DeviceSetClaimStatus) DeepCopy() *DeviceSetClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDeviceClaim) DeepCopyInto(out *PodDeviceClaim) {
+	*out = *in
+	if in.DeviceClaimSpec != nil {
+		in, out := &in.DeviceClaimSpec, &out.DeviceClaimSpec
+		*out = new(DeviceClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeviceClaimName != nil {
+		in, out := &in.DeviceClaimName, &out.DeviceClaimName
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeviceClaimTemplateName != nil {
+		in, out := &in.DeviceClaimTemplateName, &out.DeviceClaimTemplateName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDeviceClaim.
+func (in *PodDeviceClaim) DeepCopy() *PodDeviceClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDeviceClaim)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,90 @@
+package cel
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+
+	"k8s.io/utils/lru"
+)
+
+// DefaultProgramCacheSize is the number of compiled constraints kept in the
+// cache.
+const DefaultProgramCacheSize = 1024
+
+// programs caches the compiled constraints, since the same constraints are
+// evaluated against every pool. A nil cache compiles every time.
+var programs = newProgramCache(DefaultProgramCacheSize)
+
+// programCache is a bounded, concurrency-safe cache of compiled CEL programs,
+// evicting the least recently used.
+type programCache struct {
+	cache *lru.Cache
+}
+
+// programKey identifies a compiled program by the expression and the
+// environment it was compiled in. Expressions compiled against different
+// attribute schemas are different programs.
+type programKey struct {
+	expr string
+	env  string
+}
+
+// compiledProgram holds the result of compiling an expression. Compilation
+// errors are cached too, since compiling again would give the same error.
+type compiledProgram struct {
+	prog cel.Program
+	err  error
+}
+
+func newProgramCache(size int) *programCache {
+	return &programCache{
+		cache: lru.New(size),
+	}
+}
+
+// get returns the compiled program for the expression, compiling it if it is
+// not in the cache.
+func (c *programCache) get(expr string, schema []api.AttributeSchema) (cel.Program, error) {
+	if c == nil {
+		return compileExpr(expr, schema)
+	}
+
+	key := programKey{expr: expr, env: envKey(schema)}
+	if v, ok := c.cache.Get(key); ok {
+		cp := v.(compiledProgram)
+		return cp.prog, cp.err
+	}
+
+	prog, err := compileExpr(expr, schema)
+	c.cache.Add(key, compiledProgram{prog: prog, err: err})
+
+	return prog, err
+}
+
+// envKey returns a string identifying the CEL environment for the schema. A
+// nil schema is the untyped environment. The order of the attributes does not
+// change the environment, so they are sorted by name.
+func envKey(schema []api.AttributeSchema) string {
+	if schema == nil {
+		return ""
+	}
+
+	sorted := slices.Clone(schema)
+	slices.SortFunc(sorted, func(a, b api.AttributeSchema) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var b strings.Builder
+	b.WriteString("typed")
+	for _, as := range sorted {
+		b.WriteString(";")
+		b.WriteString(as.Name)
+		b.WriteString(":")
+		b.WriteString(string(as.Type))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,248 @@
+// Package cel compiles and evaluates the CEL constraints of device classes and
+// claims against the attributes of devices. It is used both by the scheduler,
+// to select devices, and by the validation of the API types, to check that
+// constraints compile.
+package cel
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+)
+
+const (
+	DeviceVarName = "device"
+
+	// ConstraintsCostLimit is the maximum runtime cost of evaluating
+	// constraints against a device, in the units of the CEL cost model.
+	ConstraintsCostLimit = 1000000
+)
+
+func MeetsConstraints(constraints *string, attrs []api.Attribute) (bool, error) {
+	if constraints == nil || *constraints == "" {
+		return true, nil
+	}
+
+	inputs := make(map[string]interface{})
+	inputs[DeviceVarName] = attributesToInputs(attrs)
+
+	return evalExpr(*constraints, inputs)
+}
+
+func attributesToInputs(attributes []api.Attribute) map[string]interface{} {
+	result := make(map[string]interface{}, len(attributes))
+
+	for _, a := range attributes {
+		if a.StringValue != nil {
+			result[a.Name] = *a.StringValue
+		} else if a.IntValue != nil {
+			result[a.Name] = *a.IntValue
+		} else if a.QuantityValue != nil {
+			q := a.QuantityValue.DeepCopy()
+			result[a.Name] = QuantityVal{&q}
+		} else if a.SemVerValue != nil {
+			// An invalid version is passed as a string, so that the
+			// mistake shows up as an error if it is compared.
+			if v, err := a.SemVerValue.Parse(); err == nil {
+				result[a.Name] = SemVerVal{v}
+			} else {
+				result[a.Name] = string(*a.SemVerValue)
+			}
+		}
+	}
+
+	return result
+}
+
+func evalExpr(expr string, inputs map[string]interface{}) (bool, error) {
+	prog, err := programs.get(expr, nil)
+	if err != nil {
+		return false, err
+	}
+
+	val, _, err := prog.Eval(inputs)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := val.ConvertToNative(reflect.TypeOf(true))
+	if err != nil {
+		return false, err
+	}
+
+	s, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned non-string value: %v", result)
+	}
+
+	return s, nil
+}
+
+// compileExpr returns a compiled CEL expression. If the attribute schema is
+// nil, the device is untyped, and mistakes in the expression are only found
+// when it is evaluated against a particular device. Otherwise, the device is
+// typed according to the schema, and the expression must return a bool.
+func compileExpr(expr string, schema []api.AttributeSchema) (cel.Program, error) {
+	var opts []cel.EnvOption
+	opts = append(opts, cel.HomogeneousAggregateLiterals())
+	opts = append(opts, cel.EagerlyValidateDeclarations(true), cel.DefaultUTCTimeZone(true))
+	opts = append(opts, SemVerLib(), QuantityLib())
+	if schema == nil {
+		opts = append(opts, cel.Variable(DeviceVarName, cel.DynType))
+	} else {
+		provider, err := newDeviceTypeProvider(schema)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, cel.CustomTypeProvider(provider))
+		opts = append(opts, cel.Variable(DeviceVarName, cel.ObjectType(deviceTypeName)))
+	}
+
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues != nil {
+		return nil, issues.Err()
+	}
+
+	if schema != nil && !ast.OutputType().IsExactType(cel.BoolType) {
+		return nil, fmt.Errorf("expression must return a bool, not %s", ast.OutputType())
+	}
+
+	_, err = cel.AstToCheckedExpr(ast)
+	if err != nil {
+		return nil, err
+	}
+	return env.Program(ast,
+		cel.EvalOptions(cel.OptOptimize),
+		cel.CostLimit(ConstraintsCostLimit),
+	)
+}
+
+// deviceTypeName is the CEL type name of the device, when the attribute schema
+// is known.
+const deviceTypeName = "devmgmtproto.k8s.io.Device"
+
+// deviceTypeProvider adds a device object type, with a field for each
+// attribute in the schema, to the standard CEL types. At evaluation time, the
+// device is still just a map of the attribute values.
+type deviceTypeProvider struct {
+	types.Provider
+	fields map[string]*types.Type
+}
+
+func newDeviceTypeProvider(schema []api.AttributeSchema) (*deviceTypeProvider, error) {
+	p := &deviceTypeProvider{
+		Provider: types.NewEmptyRegistry(),
+		fields:   make(map[string]*types.Type),
+	}
+
+	for _, as := range schema {
+		t, err := attributeCELType(as.Type)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", as.Name, err)
+		}
+		p.fields[as.Name] = t
+	}
+
+	return p, nil
+}
+
+func (p *deviceTypeProvider) FindStructType(structType string) (*types.Type, bool) {
+	if structType == deviceTypeName {
+		return types.NewTypeTypeWithParam(types.NewObjectType(deviceTypeName)), true
+	}
+
+	return p.Provider.FindStructType(structType)
+}
+
+func (p *deviceTypeProvider) FindStructFieldNames(structType string) ([]string, bool) {
+	if structType != deviceTypeName {
+		return p.Provider.FindStructFieldNames(structType)
+	}
+
+	var names []string
+	for name := range p.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, true
+}
+
+func (p *deviceTypeProvider) FindStructFieldType(structType, fieldName string) (*types.FieldType, bool) {
+	if structType != deviceTypeName {
+		return p.Provider.FindStructFieldType(structType, fieldName)
+	}
+
+	t, ok := p.fields[fieldName]
+	if !ok {
+		return nil, false
+	}
+
+	return &types.FieldType{Type: t}, true
+}
+
+// attributeCELType returns the CEL type used for values of the attribute type.
+func attributeCELType(t api.AttributeType) (*types.Type, error) {
+	switch t {
+	case api.AttributeTypeString:
+		return types.StringType, nil
+	case api.AttributeTypeInt:
+		return types.IntType, nil
+	case api.AttributeTypeQuantity:
+		return QuantityType, nil
+	case api.AttributeTypeSemVer:
+		return SemVerType, nil
+	}
+
+	return nil, fmt.Errorf("unknown attribute type %q", t)
+}
+
+// AttributeSchemaForDrivers combines the attribute schemas of the drivers. It
+// returns nil if any driver does not publish a schema, since then the
+// attributes of its devices are unknown. It is an error for drivers to
+// declare different types for the same attribute.
+func AttributeSchemaForDrivers(drivers []api.DeviceDriver) ([]api.AttributeSchema, error) {
+	if len(drivers) == 0 {
+		return nil, nil
+	}
+
+	var schema []api.AttributeSchema
+	declared := make(map[string]api.AttributeType)
+	for _, d := range drivers {
+		if d.AttributeSchema == nil {
+			return nil, nil
+		}
+
+		for _, as := range d.AttributeSchema {
+			t, ok := declared[as.Name]
+			if !ok {
+				declared[as.Name] = as.Type
+				schema = append(schema, as)
+				continue
+			}
+
+			if t != as.Type {
+				return nil, fmt.Errorf("drivers declare attribute %q as both %s and %s", as.Name, t, as.Type)
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+// CompileConstraints checks that the constraints compile. With a nil schema,
+// the device attributes are untyped, so this only catches syntax errors and
+// misuse of functions and literals.
+func CompileConstraints(constraints string, schema []api.AttributeSchema) error {
+	_, err := programs.get(constraints, schema)
+	return err
+}
@@ -0,0 +1,416 @@
+package cel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/gen"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func ptr[T any](val T) *T {
+	var v T = val
+	return &v
+}
+
+func TestMeetsConstraints(t *testing.T) {
+	testCases := map[string]struct {
+		constraints *string
+		attrs       []api.Attribute
+		expErr      string
+		result      bool
+	}{
+		"nil constraint": {
+			constraints: nil,
+			result:      true,
+		},
+		"empty constraint": {
+			constraints: ptr(""),
+			result:      true,
+		},
+		"simple constraint met": {
+			constraints: ptr("device.vendor == 'example.com'"),
+			attrs: []api.Attribute{
+				{
+					Name:        "vendor",
+					StringValue: ptr("example.com"),
+				},
+			},
+			result: true,
+		},
+		"simple constraint failed": {
+			constraints: ptr("device.vendor == 'example.com'"),
+			attrs: []api.Attribute{
+				{
+					Name:        "vendor",
+					StringValue: ptr("example.org"),
+				},
+			},
+			result: false,
+		},
+		"multi-attribute constraint met": {
+			constraints: ptr("device.vendor == 'example.com' && device.model == 'foozer-1000'"),
+			attrs: []api.Attribute{
+				{
+					Name:        "vendor",
+					StringValue: ptr("example.com"),
+				},
+				{
+					Name:        "model",
+					StringValue: ptr("foozer-1000"),
+				},
+			},
+			result: true,
+		},
+		"simple device and pool constraint failed": {
+			constraints: ptr("device.vendor == 'example.com' && device.model == 'foozer-1000'"),
+			attrs: []api.Attribute{
+				{
+					Name:        "vendor",
+					StringValue: ptr("example.org"),
+				},
+				{
+					Name:        "model",
+					StringValue: ptr("foozer-1000"),
+				},
+			},
+			result: false,
+		},
+		"quantity constraint met": {
+			constraints: ptr("device.memory >= quantity('10Gi')"),
+			attrs: []api.Attribute{
+				{
+					Name:          "memory",
+					QuantityValue: ptr(resource.MustParse("10Gi")),
+				},
+			},
+			result: true,
+		},
+		"quantity compared to string": {
+			constraints: ptr("device.memory >= '10Gi'"),
+			attrs: []api.Attribute{
+				{
+					Name:          "memory",
+					QuantityValue: ptr(resource.MustParse("10Gi")),
+				},
+			},
+			expErr: "no such overload",
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			result, err := MeetsConstraints(tc.constraints, tc.attrs)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				require.Equal(t, tc.result, result)
+			} else {
+				require.EqualError(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestCheckConstraints(t *testing.T) {
+	schema := []api.AttributeSchema{
+		{Name: "vendor", Type: api.AttributeTypeString},
+		{Name: "model", Type: api.AttributeTypeString},
+		{Name: "cores", Type: api.AttributeTypeInt},
+		{Name: "memory", Type: api.AttributeTypeQuantity},
+		{Name: "firmwareVersion", Type: api.AttributeTypeSemVer},
+	}
+
+	testCases := map[string]struct {
+		constraints string
+		expErr      string
+	}{
+		"valid constraint": {
+			constraints: "device.vendor == 'example.com' && device.cores > 4",
+		},
+		"misspelled attribute": {
+			constraints: "device.modle == 'foozer-1000'",
+			expErr:      "undefined field 'modle'",
+		},
+		"string compared to int": {
+			constraints: "device.vendor == 4",
+			expErr:      "found no matching overload for '_==_' applied to '(string, int)'",
+		},
+		"not a bool": {
+			constraints: "device.vendor",
+			expErr:      "expression must return a bool, not string",
+		},
+		"has macro": {
+			constraints: "has(device.firmwareVersion)",
+		},
+		"semver compared to semver": {
+			constraints: "device.firmwareVersion >= semver('4.2.0')",
+		},
+		"quantity compared to quantity": {
+			constraints: "device.memory >= quantity('40Gi')",
+		},
+		"quantity compared to string": {
+			constraints: "device.memory >= '40Gi'",
+			expErr:      "found no matching overload for '_>=_' applied to '(devmgmtproto.Quantity, string)'",
+		},
+		"semver compared to string": {
+			constraints: "device.firmwareVersion >= '4.2.0'",
+			expErr:      "found no matching overload for '_>=_' applied to '(devmgmtproto.SemVer, string)'",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			_, err := compileExpr(tc.constraints, schema)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestProgramCache(t *testing.T) {
+	cache := newProgramCache(2)
+
+	first, err := cache.get("device.vendor == 'example.com'", nil)
+	require.NoError(t, err)
+	again, err := cache.get("device.vendor == 'example.com'", nil)
+	require.NoError(t, err)
+	require.True(t, first == again, "expected the cached program")
+
+	// the same expression in a different environment is a different program
+	_, err = cache.get("device.vendor == 'example.com'", []api.AttributeSchema{{Name: "vendor", Type: api.AttributeTypeString}})
+	require.NoError(t, err)
+	require.Equal(t, 2, cache.cache.Len())
+
+	// errors are cached too
+	_, err = cache.get("device.vendor ==", nil)
+	require.Error(t, err)
+	require.Equal(t, 2, cache.cache.Len())
+	_, err = cache.get("device.vendor ==", nil)
+	require.Error(t, err)
+}
+
+func TestEnvKeyAttributeOrder(t *testing.T) {
+	vendor := api.AttributeSchema{Name: "vendor", Type: api.AttributeTypeString}
+	numa := api.AttributeSchema{Name: "numa", Type: api.AttributeTypeInt}
+
+	schema := []api.AttributeSchema{vendor, numa}
+	require.Equal(t, envKey(schema), envKey([]api.AttributeSchema{numa, vendor}))
+	require.Equal(t, []api.AttributeSchema{vendor, numa}, schema, "the schema must not be sorted in place")
+	require.NotEqual(t, envKey(schema), envKey([]api.AttributeSchema{vendor}))
+
+	// the same expression compiled against either ordering is cached once
+	cache := newProgramCache(2)
+	first, err := cache.get("device.numa == 0", []api.AttributeSchema{vendor, numa})
+	require.NoError(t, err)
+	again, err := cache.get("device.numa == 0", []api.AttributeSchema{numa, vendor})
+	require.NoError(t, err)
+	require.True(t, first == again, "expected the cached program")
+	require.Equal(t, 1, cache.cache.Len())
+}
+
+func TestConstraintsCostLimit(t *testing.T) {
+	list := "[" + strings.Repeat("1, ", 99) + "1]"
+	expr := fmt.Sprintf("%s.all(a, %s.all(b, %s.all(c, device.vendor == 'example.com')))", list, list, list)
+
+	_, err := MeetsConstraints(ptr(expr), []api.Attribute{
+		{
+			Name:        "vendor",
+			StringValue: ptr("example.com"),
+		},
+	})
+	require.ErrorContains(t, err, "cost limit exceeded")
+}
+
+// BenchmarkMeetsConstraints evaluates the constraints of a claim against all
+// the pools of a large cluster, as SelectNode does, with and without caching
+// the compiled constraints.
+func BenchmarkMeetsConstraints(b *testing.B) {
+	constraints := ptr("device.vendor == 'example.com' && device.model == 'foozer-1000' && device.numa == '0'")
+	pools := gen.GenFoozerBarzerNodes(1000)
+
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cached=%v", cached), func(b *testing.B) {
+			saved := programs
+			defer func() { programs = saved }()
+			if !cached {
+				programs = nil
+			}
+
+			for i := 0; i < b.N; i++ {
+				for _, p := range pools {
+					if _, err := MeetsConstraints(constraints, p.Spec.Attributes); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSemVerConstraints(t *testing.T) {
+	attrs := []api.Attribute{
+		{Name: "firmwareVersion", SemVerValue: ptr(api.SemVer("4.2.1-gen3"))},
+		{Name: "driverVersion", SemVerValue: ptr(api.SemVer("10.0.0"))},
+		{Name: "badVersion", SemVerValue: ptr(api.SemVer("4.2"))},
+	}
+
+	testCases := map[string]struct {
+		constraints string
+		expErr      string
+		result      bool
+	}{
+		"greater than or equal": {
+			constraints: "device.firmwareVersion >= semver('4.2.0')",
+			result:      true,
+		},
+		"not compared lexically": {
+			constraints: "device.driverVersion > semver('9.1.0')",
+			result:      true,
+		},
+		"prerelease is less than the release": {
+			constraints: "device.firmwareVersion < semver('4.2.1')",
+			result:      true,
+		},
+		"prereleases compared": {
+			constraints: "device.firmwareVersion.isLessThan(semver('4.2.1-gen7'))",
+			result:      true,
+		},
+		"equal": {
+			constraints: "device.driverVersion == semver('10.0.0+build.1')",
+			result:      true,
+		},
+		"is greater than": {
+			constraints: "device.driverVersion.isGreaterThan(device.firmwareVersion)",
+			result:      true,
+		},
+		"compare to": {
+			constraints: "device.firmwareVersion.compareTo(semver('4.2.1-gen3')) == 0",
+			result:      true,
+		},
+		"parts": {
+			constraints: "device.firmwareVersion.major() == 4 && device.firmwareVersion.minor() == 2 && device.firmwareVersion.patch() == 1",
+			result:      true,
+		},
+		"prerelease": {
+			constraints: "device.firmwareVersion.isPrerelease() && device.firmwareVersion.prerelease() == 'gen3' && !device.driverVersion.isPrerelease()",
+			result:      true,
+		},
+		"is semver": {
+			constraints: "isSemver('1.2.3') && !isSemver('1.2')",
+			result:      true,
+		},
+		"invalid literal": {
+			constraints: "device.firmwareVersion > semver('4.2')",
+			expErr:      "invalid semantic version \"4.2\"",
+		},
+		"invalid attribute": {
+			constraints: "device.badVersion > semver('4.2.0')",
+			expErr:      "no such overload",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			result, err := MeetsConstraints(ptr(tc.constraints), attrs)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				require.Equal(t, tc.result, result)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestQuantityConstraints(t *testing.T) {
+	attrs := []api.Attribute{
+		{Name: "memory", QuantityValue: ptr(resource.MustParse("40Gi"))},
+		{Name: "cores", QuantityValue: ptr(resource.MustParse("1500m"))},
+		{Name: "huge", QuantityValue: ptr(resource.MustParse("100E"))},
+	}
+
+	testCases := map[string]struct {
+		constraints string
+		expErr      string
+		result      bool
+	}{
+		"at least": {
+			constraints: "device.memory >= quantity('40Gi')",
+			result:      true,
+		},
+		"different units": {
+			constraints: "device.memory > quantity('40G') && device.memory < quantity('43G')",
+			result:      true,
+		},
+		"equal": {
+			constraints: "device.cores == quantity('1.5')",
+			result:      true,
+		},
+		"is greater than": {
+			constraints: "device.memory.isGreaterThan(quantity('32Gi'))",
+			result:      true,
+		},
+		"is less than": {
+			constraints: "device.memory.isLessThan(quantity('32Gi'))",
+			result:      false,
+		},
+		"compare to": {
+			constraints: "device.memory.compareTo(quantity('40Gi')) == 0",
+			result:      true,
+		},
+		"operators": {
+			constraints: "device.memory - quantity('8Gi') == quantity('32Gi') && device.cores + quantity('500m') == quantity('2')",
+			result:      true,
+		},
+		"add and sub": {
+			constraints: "device.memory.sub(quantity('8Gi')).add(1) == quantity('32Gi').add(1) && device.cores.add(1).sub(quantity('500m')) == quantity('2')",
+			result:      true,
+		},
+		"as integer": {
+			constraints: "device.memory.asInteger() == 40 * 1024 * 1024 * 1024",
+			result:      true,
+		},
+		"as integer of a fraction": {
+			constraints: "device.cores.asInteger() == 1",
+			expErr:      "quantity 1500m is not a whole number",
+		},
+		"as integer out of range": {
+			constraints: "device.huge.asInteger() > 0",
+			expErr:      "quantity 100E is out of range for an int",
+		},
+		"is integer": {
+			constraints: "device.memory.isInteger() && !device.cores.isInteger() && !device.huge.isInteger()",
+			result:      true,
+		},
+		"as approximate float": {
+			constraints: "device.cores.asApproximateFloat() == 1.5",
+			result:      true,
+		},
+		"is quantity": {
+			constraints: "isQuantity('10Gi') && !isQuantity('10 gigs')",
+			result:      true,
+		},
+		"invalid literal": {
+			constraints: "device.memory > quantity('10 gigs')",
+			expErr:      "invalid quantity \"10 gigs\"",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			result, err := MeetsConstraints(ptr(tc.constraints), attrs)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				require.Equal(t, tc.result, result)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package cel
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// QuantityType is the CEL type of quantities. Quantity attributes are passed
+// to CEL with this type.
+var QuantityType = cel.ObjectType("devmgmtproto.Quantity", traits.ComparerType, traits.AdderType, traits.SubtractorType)
+
+// QuantityLib adds resource quantities to CEL:
+//
+//	quantity(string) quantity     parses a quantity, failing if it is invalid
+//	isQuantity(string) bool       checks whether a string is a valid quantity
+//	<quantity>.compareTo(quantity) int
+//	<quantity>.isGreaterThan(quantity) bool
+//	<quantity>.isLessThan(quantity) bool
+//	<quantity>.add(quantity | int) quantity
+//	<quantity>.sub(quantity | int) quantity
+//	<quantity>.isInteger() bool   true if asInteger would succeed
+//	<quantity>.asInteger() int    fails if the value is not a whole number, or too large
+//	<quantity>.asApproximateFloat() double
+//
+// Quantities may also be compared with ==, !=, <, <=, > and >=, and added
+// and subtracted with + and -. For example:
+//
+//	device.memory >= quantity('40Gi')
+//	device.memory - quantity('8Gi') > quantity('32Gi')
+func QuantityLib() cel.EnvOption {
+	return cel.Lib(quantityLib{})
+}
+
+type quantityLib struct{}
+
+func (quantityLib) CompileOptions() []cel.EnvOption {
+	// The standard operators dispatch to the methods of the values, so
+	// the overloads only need to be declared.
+	operator := func(name, id string, resultType *cel.Type) cel.EnvOption {
+		return cel.Function(name,
+			cel.Overload(id, []*cel.Type{QuantityType, QuantityType}, resultType))
+	}
+
+	return []cel.EnvOption{
+		cel.Function("quantity",
+			cel.Overload("quantity_string", []*cel.Type{cel.StringType}, QuantityType,
+				cel.UnaryBinding(func(arg ref.Val) ref.Val {
+					s, ok := arg.(types.String)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(arg)
+					}
+
+					q, err := resource.ParseQuantity(string(s))
+					if err != nil {
+						return types.WrapErr(fmt.Errorf("invalid quantity %q: %w", string(s), err))
+					}
+
+					return QuantityVal{&q}
+				}))),
+		cel.Function("isQuantity",
+			cel.Overload("is_quantity_string", []*cel.Type{cel.StringType}, cel.BoolType,
+				cel.UnaryBinding(func(arg ref.Val) ref.Val {
+					s, ok := arg.(types.String)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(arg)
+					}
+
+					_, err := resource.ParseQuantity(string(s))
+					return types.Bool(err == nil)
+				}))),
+		cel.Function("compareTo",
+			cel.MemberOverload("quantity_compare_to_quantity", []*cel.Type{QuantityType, QuantityType}, cel.IntType,
+				binaryQuantity(QuantityVal.Compare))),
+		cel.Function("isGreaterThan",
+			cel.MemberOverload("quantity_is_greater_than_quantity", []*cel.Type{QuantityType, QuantityType}, cel.BoolType,
+				binaryQuantity(func(q QuantityVal, other ref.Val) ref.Val {
+					c, ok := q.Compare(other).(types.Int)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(other)
+					}
+					return types.Bool(c > 0)
+				}))),
+		cel.Function("isLessThan",
+			cel.MemberOverload("quantity_is_less_than_quantity", []*cel.Type{QuantityType, QuantityType}, cel.BoolType,
+				binaryQuantity(func(q QuantityVal, other ref.Val) ref.Val {
+					c, ok := q.Compare(other).(types.Int)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(other)
+					}
+					return types.Bool(c < 0)
+				}))),
+		cel.Function("add",
+			cel.MemberOverload("quantity_add_quantity", []*cel.Type{QuantityType, QuantityType}, QuantityType,
+				binaryQuantity(QuantityVal.Add)),
+			cel.MemberOverload("quantity_add_int", []*cel.Type{QuantityType, cel.IntType}, QuantityType,
+				binaryQuantity(func(q QuantityVal, other ref.Val) ref.Val {
+					return q.Add(intQuantity(other))
+				}))),
+		cel.Function("sub",
+			cel.MemberOverload("quantity_sub_quantity", []*cel.Type{QuantityType, QuantityType}, QuantityType,
+				binaryQuantity(QuantityVal.Subtract)),
+			cel.MemberOverload("quantity_sub_int", []*cel.Type{QuantityType, cel.IntType}, QuantityType,
+				binaryQuantity(func(q QuantityVal, other ref.Val) ref.Val {
+					return q.Subtract(intQuantity(other))
+				}))),
+		cel.Function("isInteger",
+			cel.MemberOverload("quantity_is_integer", []*cel.Type{QuantityType}, cel.BoolType,
+				unaryQuantity(func(q QuantityVal) ref.Val {
+					_, err := q.asInteger()
+					return types.Bool(err == nil)
+				}))),
+		cel.Function("asInteger",
+			cel.MemberOverload("quantity_as_integer", []*cel.Type{QuantityType}, cel.IntType,
+				unaryQuantity(func(q QuantityVal) ref.Val {
+					i, err := q.asInteger()
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return types.Int(i)
+				}))),
+		cel.Function("asApproximateFloat",
+			cel.MemberOverload("quantity_as_approximate_float", []*cel.Type{QuantityType}, cel.DoubleType,
+				unaryQuantity(func(q QuantityVal) ref.Val {
+					return types.Double(q.AsApproximateFloat64())
+				}))),
+		operator(operators.Less, "less_quantity", cel.BoolType),
+		operator(operators.LessEquals, "less_equals_quantity", cel.BoolType),
+		operator(operators.Greater, "greater_quantity", cel.BoolType),
+		operator(operators.GreaterEquals, "greater_equals_quantity", cel.BoolType),
+		operator(operators.Add, "add_quantity", QuantityType),
+		operator(operators.Subtract, "subtract_quantity", QuantityType),
+	}
+}
+
+func (quantityLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+// unaryQuantity binds a function of a quantity.
+func unaryQuantity(fn func(QuantityVal) ref.Val) cel.OverloadOpt {
+	return cel.UnaryBinding(func(arg ref.Val) ref.Val {
+		q, ok := arg.(QuantityVal)
+		if !ok {
+			return types.MaybeNoSuchOverloadErr(arg)
+		}
+
+		return fn(q)
+	})
+}
+
+// binaryQuantity binds a function of a quantity and another value.
+func binaryQuantity(fn func(QuantityVal, ref.Val) ref.Val) cel.OverloadOpt {
+	return cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+		q, ok := lhs.(QuantityVal)
+		if !ok {
+			return types.MaybeNoSuchOverloadErr(lhs)
+		}
+
+		return fn(q, rhs)
+	})
+}
+
+func intQuantity(val ref.Val) ref.Val {
+	i, ok := val.(types.Int)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(val)
+	}
+
+	return QuantityVal{resource.NewQuantity(int64(i), resource.DecimalSI)}
+}
+
+// QuantityVal is the CEL value of a resource quantity.
+type QuantityVal struct {
+	*resource.Quantity
+}
+
+var _ ref.Val = QuantityVal{}
+var _ traits.Comparer = QuantityVal{}
+var _ traits.Adder = QuantityVal{}
+var _ traits.Subtractor = QuantityVal{}
+
+// asInteger returns the quantity as an int64, failing if it is not a whole
+// number or does not fit.
+func (q QuantityVal) asInteger() (int64, error) {
+	if q.CmpInt64(math.MaxInt64) > 0 || q.CmpInt64(math.MinInt64) < 0 {
+		return 0, fmt.Errorf("quantity %s is out of range for an int", q.String())
+	}
+
+	i, ok := q.AsInt64()
+	if !ok {
+		return 0, fmt.Errorf("quantity %s is not a whole number", q.String())
+	}
+
+	return i, nil
+}
+
+func (q QuantityVal) Compare(other ref.Val) ref.Val {
+	o, ok := other.(QuantityVal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+
+	return types.Int(q.Cmp(*o.Quantity))
+}
+
+func (q QuantityVal) Add(other ref.Val) ref.Val {
+	o, ok := other.(QuantityVal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+
+	result := q.DeepCopy()
+	result.Add(*o.Quantity)
+	return QuantityVal{&result}
+}
+
+func (q QuantityVal) Subtract(other ref.Val) ref.Val {
+	o, ok := other.(QuantityVal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+
+	result := q.DeepCopy()
+	result.Sub(*o.Quantity)
+	return QuantityVal{&result}
+}
+
+func (q QuantityVal) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case reflect.TypeOf(q.Quantity):
+		return q.Quantity, nil
+	case reflect.TypeOf(resource.Quantity{}):
+		return *q.Quantity, nil
+	case reflect.TypeOf(""):
+		return q.String(), nil
+	}
+
+	return nil, fmt.Errorf("type conversion error from quantity to '%v'", typeDesc)
+}
+
+func (q QuantityVal) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case QuantityType:
+		return q
+	case types.StringType:
+		return types.String(q.String())
+	case types.TypeType:
+		return QuantityType
+	}
+
+	return types.NewErr("type conversion error from quantity to '%s'", typeVal)
+}
+
+func (q QuantityVal) Equal(other ref.Val) ref.Val {
+	o, ok := other.(QuantityVal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+
+	return types.Bool(q.Cmp(*o.Quantity) == 0)
+}
+
+func (q QuantityVal) Type() ref.Type {
+	return QuantityType
+}
+
+func (q QuantityVal) Value() any {
+	return q.Quantity
+}
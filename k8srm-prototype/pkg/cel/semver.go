@@ -0,0 +1,217 @@
+package cel
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// SemVerType is the CEL type of semantic versions. SemVer attributes are
+// passed to CEL with this type, so that they compare by version precedence
+// rather than lexically.
+var SemVerType = cel.ObjectType("devmgmtproto.SemVer", traits.ComparerType)
+
+// SemVerLib adds semantic versions to CEL:
+//
+//	semver(string) semver         parses a version, failing if it is invalid
+//	isSemver(string) bool         checks whether a string is a valid version
+//	<semver>.compareTo(semver) int
+//	<semver>.isGreaterThan(semver) bool
+//	<semver>.isLessThan(semver) bool
+//	<semver>.major() int
+//	<semver>.minor() int
+//	<semver>.patch() int
+//	<semver>.prerelease() string  empty if this is not a prerelease
+//	<semver>.isPrerelease() bool
+//
+// Versions may also be compared with ==, !=, <, <=, > and >=. For example:
+//
+//	device.firmwareVersion >= semver('4.2.0')
+//
+// A prerelease version is less than the release version, so 4.2.1-gen3 is
+// less than 4.2.1, but greater than 4.2.0.
+func SemVerLib() cel.EnvOption {
+	return cel.Lib(semverLib{})
+}
+
+type semverLib struct{}
+
+func (semverLib) CompileOptions() []cel.EnvOption {
+	// The standard comparison operators dispatch to the Compare method of
+	// the values, so the overloads only need to be declared.
+	compare := func(name, id string) cel.EnvOption {
+		return cel.Function(name,
+			cel.Overload(id, []*cel.Type{SemVerType, SemVerType}, cel.BoolType))
+	}
+
+	return []cel.EnvOption{
+		cel.Function("semver",
+			cel.Overload("semver_string", []*cel.Type{cel.StringType}, SemVerType,
+				cel.UnaryBinding(func(arg ref.Val) ref.Val {
+					s, ok := arg.(types.String)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(arg)
+					}
+
+					v, err := api.SemVer(s).Parse()
+					if err != nil {
+						return types.WrapErr(err)
+					}
+
+					return SemVerVal{v}
+				}))),
+		cel.Function("isSemver",
+			cel.Overload("is_semver_string", []*cel.Type{cel.StringType}, cel.BoolType,
+				cel.UnaryBinding(func(arg ref.Val) ref.Val {
+					s, ok := arg.(types.String)
+					if !ok {
+						return types.MaybeNoSuchOverloadErr(arg)
+					}
+
+					return types.Bool(api.SemVer(s).Validate() == nil)
+				}))),
+		cel.Function("compareTo",
+			cel.MemberOverload("semver_compare_to_semver", []*cel.Type{SemVerType, SemVerType}, cel.IntType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					c, err := compareSemVers(lhs, rhs)
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return types.Int(c)
+				}))),
+		cel.Function("isGreaterThan",
+			cel.MemberOverload("semver_is_greater_than_semver", []*cel.Type{SemVerType, SemVerType}, cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					c, err := compareSemVers(lhs, rhs)
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return types.Bool(c > 0)
+				}))),
+		cel.Function("isLessThan",
+			cel.MemberOverload("semver_is_less_than_semver", []*cel.Type{SemVerType, SemVerType}, cel.BoolType,
+				cel.BinaryBinding(func(lhs, rhs ref.Val) ref.Val {
+					c, err := compareSemVers(lhs, rhs)
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return types.Bool(c < 0)
+				}))),
+		semverAccessor("major", func(v *version.Version) ref.Val { return types.Int(v.Major()) }, cel.IntType),
+		semverAccessor("minor", func(v *version.Version) ref.Val { return types.Int(v.Minor()) }, cel.IntType),
+		semverAccessor("patch", func(v *version.Version) ref.Val { return types.Int(v.Patch()) }, cel.IntType),
+		semverAccessor("prerelease", func(v *version.Version) ref.Val { return types.String(v.PreRelease()) }, cel.StringType),
+		semverAccessor("isPrerelease", func(v *version.Version) ref.Val { return types.Bool(v.PreRelease() != "") }, cel.BoolType),
+		compare(operators.Less, "less_semver"),
+		compare(operators.LessEquals, "less_equals_semver"),
+		compare(operators.Greater, "greater_semver"),
+		compare(operators.GreaterEquals, "greater_equals_semver"),
+	}
+}
+
+func (semverLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+// semverAccessor declares a member function returning a part of the version.
+func semverAccessor(name string, fn func(*version.Version) ref.Val, resultType *cel.Type) cel.EnvOption {
+	return cel.Function(name,
+		cel.MemberOverload("semver_"+name, []*cel.Type{SemVerType}, resultType,
+			cel.UnaryBinding(func(arg ref.Val) ref.Val {
+				v, ok := arg.(SemVerVal)
+				if !ok {
+					return types.MaybeNoSuchOverloadErr(arg)
+				}
+
+				return fn(v.Version)
+			})))
+}
+
+func compareSemVers(lhs, rhs ref.Val) (int, error) {
+	l, ok := lhs.(SemVerVal)
+	if !ok {
+		return 0, fmt.Errorf("no such overload: %s is not a semver", lhs.Type().TypeName())
+	}
+
+	r, ok := rhs.(SemVerVal)
+	if !ok {
+		return 0, fmt.Errorf("no such overload: %s is not a semver", rhs.Type().TypeName())
+	}
+
+	switch {
+	case l.LessThan(r.Version):
+		return -1, nil
+	case r.LessThan(l.Version):
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// SemVerVal is the CEL value of a semantic version.
+type SemVerVal struct {
+	*version.Version
+}
+
+var _ ref.Val = SemVerVal{}
+var _ traits.Comparer = SemVerVal{}
+
+func (v SemVerVal) Compare(other ref.Val) ref.Val {
+	c, err := compareSemVers(v, other)
+	if err != nil {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+
+	return types.Int(c)
+}
+
+func (v SemVerVal) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case reflect.TypeOf(v.Version):
+		return v.Version, nil
+	case reflect.TypeOf(api.SemVer("")):
+		return api.SemVer(v.String()), nil
+	case reflect.TypeOf(""):
+		return v.String(), nil
+	}
+
+	return nil, fmt.Errorf("type conversion error from semver to '%v'", typeDesc)
+}
+
+func (v SemVerVal) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case SemVerType:
+		return v
+	case types.StringType:
+		return types.String(v.String())
+	case types.TypeType:
+		return SemVerType
+	}
+
+	return types.NewErr("type conversion error from semver to '%s'", typeVal)
+}
+
+func (v SemVerVal) Equal(other ref.Val) ref.Val {
+	c, err := compareSemVers(v, other)
+	if err != nil {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+
+	return types.Bool(c == 0)
+}
+
+func (v SemVerVal) Type() ref.Type {
+	return SemVerType
+}
+
+func (v SemVerVal) Value() any {
+	return v.Version
+}
@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	scheme "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// DeviceClaimsGetter has a method to return a DeviceClaimInterface.
+// A group's client should implement this interface.
+type DeviceClaimsGetter interface {
+	DeviceClaims(namespace string) DeviceClaimInterface
+}
+
+// DeviceClaimInterface has methods to work with DeviceClaim resources.
+type DeviceClaimInterface interface {
+	Create(ctx context.Context, deviceClaim *v1alpha1.DeviceClaim, opts v1.CreateOptions) (*v1alpha1.DeviceClaim, error)
+	Update(ctx context.Context, deviceClaim *v1alpha1.DeviceClaim, opts v1.UpdateOptions) (*v1alpha1.DeviceClaim, error)
+	UpdateStatus(ctx context.Context, deviceClaim *v1alpha1.DeviceClaim, opts v1.UpdateOptions) (*v1alpha1.DeviceClaim, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.DeviceClaim, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.DeviceClaimList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceClaim, err error)
+	DeviceClaimExpansion
+}
+
+// deviceClaims implements DeviceClaimInterface
+type deviceClaims struct {
+	client rest.Interface
+	ns     string
+}
+
+// newDeviceClaims returns a DeviceClaims
+func newDeviceClaims(c *DevmgmtprotoV1alpha1Client, namespace string) *deviceClaims {
+	return &deviceClaims{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the deviceClaim, and returns the corresponding deviceClaim object, and an error if there is any.
+func (c *deviceClaims) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceClaim, err error) {
+	result = &v1alpha1.DeviceClaim{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceclaims").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of DeviceClaims that match those selectors.
+func (c *deviceClaims) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceClaimList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.DeviceClaimList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceclaims").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested deviceClaims.
+func (c *deviceClaims) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceclaims").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a deviceClaim and creates it.  Returns the server's representation of the deviceClaim, and an error, if there is any.
+func (c *deviceClaims) Create(ctx context.Context, deviceClaim *v1alpha1.DeviceClaim, opts v1.CreateOptions) (result *v1alpha1.DeviceClaim, err error) {
+	result = &v1alpha1.DeviceClaim{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("deviceclaims").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceClaim).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a deviceClaim and updates it. Returns the server's representation of the deviceClaim, and an error, if there is any.
+func (c *deviceClaims) Update(ctx context.Context, deviceClaim *v1alpha1.DeviceClaim, opts v1.UpdateOptions) (result *v1alpha1.DeviceClaim, err error) {
+	result = &v1alpha1.DeviceClaim{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("deviceclaims").
+		Name(deviceClaim.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceClaim).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *deviceClaims) UpdateStatus(ctx context.Context, deviceClaim *v1alpha1.DeviceClaim, opts v1.UpdateOptions) (result *v1alpha1.DeviceClaim, err error) {
+	result = &v1alpha1.DeviceClaim{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("deviceclaims").
+		Name(deviceClaim.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceClaim).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the deviceClaim and deletes it. Returns an error if one occurs.
+func (c *deviceClaims) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("deviceclaims").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *deviceClaims) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("deviceclaims").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched deviceClaim.
+func (c *deviceClaims) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceClaim, err error) {
+	result = &v1alpha1.DeviceClaim{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("deviceclaims").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
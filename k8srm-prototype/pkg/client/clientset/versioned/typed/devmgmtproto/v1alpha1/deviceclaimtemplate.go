@@ -0,0 +1,178 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	scheme "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// DeviceClaimTemplatesGetter has a method to return a DeviceClaimTemplateInterface.
+// A group's client should implement this interface.
+type DeviceClaimTemplatesGetter interface {
+	DeviceClaimTemplates(namespace string) DeviceClaimTemplateInterface
+}
+
+// DeviceClaimTemplateInterface has methods to work with DeviceClaimTemplate resources.
+type DeviceClaimTemplateInterface interface {
+	Create(ctx context.Context, deviceClaimTemplate *v1alpha1.DeviceClaimTemplate, opts v1.CreateOptions) (*v1alpha1.DeviceClaimTemplate, error)
+	Update(ctx context.Context, deviceClaimTemplate *v1alpha1.DeviceClaimTemplate, opts v1.UpdateOptions) (*v1alpha1.DeviceClaimTemplate, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.DeviceClaimTemplate, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.DeviceClaimTemplateList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceClaimTemplate, err error)
+	DeviceClaimTemplateExpansion
+}
+
+// deviceClaimTemplates implements DeviceClaimTemplateInterface
+type deviceClaimTemplates struct {
+	client rest.Interface
+	ns     string
+}
+
+// newDeviceClaimTemplates returns a DeviceClaimTemplates
+func newDeviceClaimTemplates(c *DevmgmtprotoV1alpha1Client, namespace string) *deviceClaimTemplates {
+	return &deviceClaimTemplates{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the deviceClaimTemplate, and returns the corresponding deviceClaimTemplate object, and an error if there is any.
+func (c *deviceClaimTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceClaimTemplate, err error) {
+	result = &v1alpha1.DeviceClaimTemplate{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceclaimtemplates").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of DeviceClaimTemplates that match those selectors.
+func (c *deviceClaimTemplates) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceClaimTemplateList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.DeviceClaimTemplateList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceclaimtemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested deviceClaimTemplates.
+func (c *deviceClaimTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceclaimtemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a deviceClaimTemplate and creates it.  Returns the server's representation of the deviceClaimTemplate, and an error, if there is any.
+func (c *deviceClaimTemplates) Create(ctx context.Context, deviceClaimTemplate *v1alpha1.DeviceClaimTemplate, opts v1.CreateOptions) (result *v1alpha1.DeviceClaimTemplate, err error) {
+	result = &v1alpha1.DeviceClaimTemplate{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("deviceclaimtemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceClaimTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a deviceClaimTemplate and updates it. Returns the server's representation of the deviceClaimTemplate, and an error, if there is any.
+func (c *deviceClaimTemplates) Update(ctx context.Context, deviceClaimTemplate *v1alpha1.DeviceClaimTemplate, opts v1.UpdateOptions) (result *v1alpha1.DeviceClaimTemplate, err error) {
+	result = &v1alpha1.DeviceClaimTemplate{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("deviceclaimtemplates").
+		Name(deviceClaimTemplate.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceClaimTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the deviceClaimTemplate and deletes it. Returns an error if one occurs.
+func (c *deviceClaimTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("deviceclaimtemplates").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *deviceClaimTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("deviceclaimtemplates").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched deviceClaimTemplate.
+func (c *deviceClaimTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceClaimTemplate, err error) {
+	result = &v1alpha1.DeviceClaimTemplate{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("deviceclaimtemplates").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,168 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	scheme "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// DeviceDriversGetter has a method to return a DeviceDriverInterface.
+// A group's client should implement this interface.
+type DeviceDriversGetter interface {
+	DeviceDrivers() DeviceDriverInterface
+}
+
+// DeviceDriverInterface has methods to work with DeviceDriver resources.
+type DeviceDriverInterface interface {
+	Create(ctx context.Context, deviceDriver *v1alpha1.DeviceDriver, opts v1.CreateOptions) (*v1alpha1.DeviceDriver, error)
+	Update(ctx context.Context, deviceDriver *v1alpha1.DeviceDriver, opts v1.UpdateOptions) (*v1alpha1.DeviceDriver, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.DeviceDriver, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.DeviceDriverList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceDriver, err error)
+	DeviceDriverExpansion
+}
+
+// deviceDrivers implements DeviceDriverInterface
+type deviceDrivers struct {
+	client rest.Interface
+}
+
+// newDeviceDrivers returns a DeviceDrivers
+func newDeviceDrivers(c *DevmgmtprotoV1alpha1Client) *deviceDrivers {
+	return &deviceDrivers{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the deviceDriver, and returns the corresponding deviceDriver object, and an error if there is any.
+func (c *deviceDrivers) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceDriver, err error) {
+	result = &v1alpha1.DeviceDriver{}
+	err = c.client.Get().
+		Resource("devicedrivers").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of DeviceDrivers that match those selectors.
+func (c *deviceDrivers) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceDriverList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.DeviceDriverList{}
+	err = c.client.Get().
+		Resource("devicedrivers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested deviceDrivers.
+func (c *deviceDrivers) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("devicedrivers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a deviceDriver and creates it.  Returns the server's representation of the deviceDriver, and an error, if there is any.
+func (c *deviceDrivers) Create(ctx context.Context, deviceDriver *v1alpha1.DeviceDriver, opts v1.CreateOptions) (result *v1alpha1.DeviceDriver, err error) {
+	result = &v1alpha1.DeviceDriver{}
+	err = c.client.Post().
+		Resource("devicedrivers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceDriver).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a deviceDriver and updates it. Returns the server's representation of the deviceDriver, and an error, if there is any.
+func (c *deviceDrivers) Update(ctx context.Context, deviceDriver *v1alpha1.DeviceDriver, opts v1.UpdateOptions) (result *v1alpha1.DeviceDriver, err error) {
+	result = &v1alpha1.DeviceDriver{}
+	err = c.client.Put().
+		Resource("devicedrivers").
+		Name(deviceDriver.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceDriver).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the deviceDriver and deletes it. Returns an error if one occurs.
+func (c *deviceDrivers) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("devicedrivers").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *deviceDrivers) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("devicedrivers").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched deviceDriver.
+func (c *deviceDrivers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceDriver, err error) {
+	result = &v1alpha1.DeviceDriver{}
+	err = c.client.Patch(pt).
+		Resource("devicedrivers").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,184 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	scheme "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// DevicePoolsGetter has a method to return a DevicePoolInterface.
+// A group's client should implement this interface.
+type DevicePoolsGetter interface {
+	DevicePools() DevicePoolInterface
+}
+
+// DevicePoolInterface has methods to work with DevicePool resources.
+type DevicePoolInterface interface {
+	Create(ctx context.Context, devicePool *v1alpha1.DevicePool, opts v1.CreateOptions) (*v1alpha1.DevicePool, error)
+	Update(ctx context.Context, devicePool *v1alpha1.DevicePool, opts v1.UpdateOptions) (*v1alpha1.DevicePool, error)
+	UpdateStatus(ctx context.Context, devicePool *v1alpha1.DevicePool, opts v1.UpdateOptions) (*v1alpha1.DevicePool, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.DevicePool, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.DevicePoolList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DevicePool, err error)
+	DevicePoolExpansion
+}
+
+// devicePools implements DevicePoolInterface
+type devicePools struct {
+	client rest.Interface
+}
+
+// newDevicePools returns a DevicePools
+func newDevicePools(c *DevmgmtprotoV1alpha1Client) *devicePools {
+	return &devicePools{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the devicePool, and returns the corresponding devicePool object, and an error if there is any.
+func (c *devicePools) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DevicePool, err error) {
+	result = &v1alpha1.DevicePool{}
+	err = c.client.Get().
+		Resource("devicepools").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of DevicePools that match those selectors.
+func (c *devicePools) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DevicePoolList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.DevicePoolList{}
+	err = c.client.Get().
+		Resource("devicepools").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested devicePools.
+func (c *devicePools) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("devicepools").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a devicePool and creates it.  Returns the server's representation of the devicePool, and an error, if there is any.
+func (c *devicePools) Create(ctx context.Context, devicePool *v1alpha1.DevicePool, opts v1.CreateOptions) (result *v1alpha1.DevicePool, err error) {
+	result = &v1alpha1.DevicePool{}
+	err = c.client.Post().
+		Resource("devicepools").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(devicePool).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a devicePool and updates it. Returns the server's representation of the devicePool, and an error, if there is any.
+func (c *devicePools) Update(ctx context.Context, devicePool *v1alpha1.DevicePool, opts v1.UpdateOptions) (result *v1alpha1.DevicePool, err error) {
+	result = &v1alpha1.DevicePool{}
+	err = c.client.Put().
+		Resource("devicepools").
+		Name(devicePool.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(devicePool).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *devicePools) UpdateStatus(ctx context.Context, devicePool *v1alpha1.DevicePool, opts v1.UpdateOptions) (result *v1alpha1.DevicePool, err error) {
+	result = &v1alpha1.DevicePool{}
+	err = c.client.Put().
+		Resource("devicepools").
+		Name(devicePool.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(devicePool).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the devicePool and deletes it. Returns an error if one occurs.
+func (c *devicePools) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("devicepools").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *devicePools) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("devicepools").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched devicePool.
+func (c *devicePools) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DevicePool, err error) {
+	result = &v1alpha1.DevicePool{}
+	err = c.client.Patch(pt).
+		Resource("devicepools").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	scheme "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// DevicePrivilegedClaimsGetter has a method to return a DevicePrivilegedClaimInterface.
+// A group's client should implement this interface.
+type DevicePrivilegedClaimsGetter interface {
+	DevicePrivilegedClaims(namespace string) DevicePrivilegedClaimInterface
+}
+
+// DevicePrivilegedClaimInterface has methods to work with DevicePrivilegedClaim resources.
+type DevicePrivilegedClaimInterface interface {
+	Create(ctx context.Context, devicePrivilegedClaim *v1alpha1.DevicePrivilegedClaim, opts v1.CreateOptions) (*v1alpha1.DevicePrivilegedClaim, error)
+	Update(ctx context.Context, devicePrivilegedClaim *v1alpha1.DevicePrivilegedClaim, opts v1.UpdateOptions) (*v1alpha1.DevicePrivilegedClaim, error)
+	UpdateStatus(ctx context.Context, devicePrivilegedClaim *v1alpha1.DevicePrivilegedClaim, opts v1.UpdateOptions) (*v1alpha1.DevicePrivilegedClaim, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.DevicePrivilegedClaim, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.DevicePrivilegedClaimList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DevicePrivilegedClaim, err error)
+	DevicePrivilegedClaimExpansion
+}
+
+// devicePrivilegedClaims implements DevicePrivilegedClaimInterface
+type devicePrivilegedClaims struct {
+	client rest.Interface
+	ns     string
+}
+
+// newDevicePrivilegedClaims returns a DevicePrivilegedClaims
+func newDevicePrivilegedClaims(c *DevmgmtprotoV1alpha1Client, namespace string) *devicePrivilegedClaims {
+	return &devicePrivilegedClaims{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the devicePrivilegedClaim, and returns the corresponding devicePrivilegedClaim object, and an error if there is any.
+func (c *devicePrivilegedClaims) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DevicePrivilegedClaim, err error) {
+	result = &v1alpha1.DevicePrivilegedClaim{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceprivilegedclaims").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of DevicePrivilegedClaims that match those selectors.
+func (c *devicePrivilegedClaims) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DevicePrivilegedClaimList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.DevicePrivilegedClaimList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceprivilegedclaims").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested devicePrivilegedClaims.
+func (c *devicePrivilegedClaims) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceprivilegedclaims").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a devicePrivilegedClaim and creates it.  Returns the server's representation of the devicePrivilegedClaim, and an error, if there is any.
+func (c *devicePrivilegedClaims) Create(ctx context.Context, devicePrivilegedClaim *v1alpha1.DevicePrivilegedClaim, opts v1.CreateOptions) (result *v1alpha1.DevicePrivilegedClaim, err error) {
+	result = &v1alpha1.DevicePrivilegedClaim{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("deviceprivilegedclaims").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(devicePrivilegedClaim).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a devicePrivilegedClaim and updates it. Returns the server's representation of the devicePrivilegedClaim, and an error, if there is any.
+func (c *devicePrivilegedClaims) Update(ctx context.Context, devicePrivilegedClaim *v1alpha1.DevicePrivilegedClaim, opts v1.UpdateOptions) (result *v1alpha1.DevicePrivilegedClaim, err error) {
+	result = &v1alpha1.DevicePrivilegedClaim{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("deviceprivilegedclaims").
+		Name(devicePrivilegedClaim.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(devicePrivilegedClaim).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *devicePrivilegedClaims) UpdateStatus(ctx context.Context, devicePrivilegedClaim *v1alpha1.DevicePrivilegedClaim, opts v1.UpdateOptions) (result *v1alpha1.DevicePrivilegedClaim, err error) {
+	result = &v1alpha1.DevicePrivilegedClaim{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("deviceprivilegedclaims").
+		Name(devicePrivilegedClaim.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(devicePrivilegedClaim).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the devicePrivilegedClaim and deletes it. Returns an error if one occurs.
+func (c *devicePrivilegedClaims) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("deviceprivilegedclaims").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *devicePrivilegedClaims) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("deviceprivilegedclaims").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched devicePrivilegedClaim.
+func (c *devicePrivilegedClaims) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DevicePrivilegedClaim, err error) {
+	result = &v1alpha1.DevicePrivilegedClaim{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("deviceprivilegedclaims").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
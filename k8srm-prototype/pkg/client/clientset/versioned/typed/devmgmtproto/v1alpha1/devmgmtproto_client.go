@@ -0,0 +1,137 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"net/http"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned/scheme"
+	rest "k8s.io/client-go/rest"
+)
+
+type DevmgmtprotoV1alpha1Interface interface {
+	RESTClient() rest.Interface
+	DeviceClaimsGetter
+	DeviceClaimTemplatesGetter
+	DeviceClassesGetter
+	DeviceDriversGetter
+	DevicePoolsGetter
+	DevicePrivilegedClaimsGetter
+	DeviceSetClaimsGetter
+}
+
+// DevmgmtprotoV1alpha1Client is used to interact with features provided by the devmgmtproto.k8s.io group.
+type DevmgmtprotoV1alpha1Client struct {
+	restClient rest.Interface
+}
+
+func (c *DevmgmtprotoV1alpha1Client) DeviceClaims(namespace string) DeviceClaimInterface {
+	return newDeviceClaims(c, namespace)
+}
+
+func (c *DevmgmtprotoV1alpha1Client) DeviceClaimTemplates(namespace string) DeviceClaimTemplateInterface {
+	return newDeviceClaimTemplates(c, namespace)
+}
+
+func (c *DevmgmtprotoV1alpha1Client) DeviceClasses() DeviceClassInterface {
+	return newDeviceClasses(c)
+}
+
+func (c *DevmgmtprotoV1alpha1Client) DeviceDrivers() DeviceDriverInterface {
+	return newDeviceDrivers(c)
+}
+
+func (c *DevmgmtprotoV1alpha1Client) DevicePools() DevicePoolInterface {
+	return newDevicePools(c)
+}
+
+func (c *DevmgmtprotoV1alpha1Client) DevicePrivilegedClaims(namespace string) DevicePrivilegedClaimInterface {
+	return newDevicePrivilegedClaims(c, namespace)
+}
+
+func (c *DevmgmtprotoV1alpha1Client) DeviceSetClaims(namespace string) DeviceSetClaimInterface {
+	return newDeviceSetClaims(c, namespace)
+}
+
+// NewForConfig creates a new DevmgmtprotoV1alpha1Client for the given config.
+// NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
+// where httpClient was generated with rest.HTTPClientFor(c).
+func NewForConfig(c *rest.Config) (*DevmgmtprotoV1alpha1Client, error) {
+	config := *c
+	if err := setConfigDefaults(&config); err != nil {
+		return nil, err
+	}
+	httpClient, err := rest.HTTPClientFor(&config)
+	if err != nil {
+		return nil, err
+	}
+	return NewForConfigAndClient(&config, httpClient)
+}
+
+// NewForConfigAndClient creates a new DevmgmtprotoV1alpha1Client for the given config and http client.
+// Note the http client provided takes precedence over the configured transport values.
+func NewForConfigAndClient(c *rest.Config, h *http.Client) (*DevmgmtprotoV1alpha1Client, error) {
+	config := *c
+	if err := setConfigDefaults(&config); err != nil {
+		return nil, err
+	}
+	client, err := rest.RESTClientForConfigAndClient(&config, h)
+	if err != nil {
+		return nil, err
+	}
+	return &DevmgmtprotoV1alpha1Client{client}, nil
+}
+
+// NewForConfigOrDie creates a new DevmgmtprotoV1alpha1Client for the given config and
+// panics if there is an error in the config.
+func NewForConfigOrDie(c *rest.Config) *DevmgmtprotoV1alpha1Client {
+	client, err := NewForConfig(c)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// New creates a new DevmgmtprotoV1alpha1Client for the given RESTClient.
+func New(c rest.Interface) *DevmgmtprotoV1alpha1Client {
+	return &DevmgmtprotoV1alpha1Client{c}
+}
+
+func setConfigDefaults(config *rest.Config) error {
+	gv := v1alpha1.SchemeGroupVersion
+	config.GroupVersion = &gv
+	config.APIPath = "/apis"
+	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+
+	if config.UserAgent == "" {
+		config.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+
+	return nil
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *DevmgmtprotoV1alpha1Client) RESTClient() rest.Interface {
+	if c == nil {
+		return nil
+	}
+	return c.restClient
+}
@@ -0,0 +1,142 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeDeviceClaims implements DeviceClaimInterface
+type FakeDeviceClaims struct {
+	Fake *FakeDevmgmtprotoV1alpha1
+	ns   string
+}
+
+var deviceclaimsResource = schema.GroupVersionResource{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Resource: "deviceclaims"}
+
+var deviceclaimsKind = schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DeviceClaim"}
+
+// Get takes name of the deviceClaim, and returns the corresponding deviceClaim object, and an error if there is any.
+func (c *FakeDeviceClaims) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(deviceclaimsResource, c.ns, name), &v1alpha1.DeviceClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClaim), err
+}
+
+// List takes label and field selectors, and returns the list of DeviceClaims that match those selectors.
+func (c *FakeDeviceClaims) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceClaimList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(deviceclaimsResource, deviceclaimsKind, c.ns, opts), &v1alpha1.DeviceClaimList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.DeviceClaimList{ListMeta: obj.(*v1alpha1.DeviceClaimList).ListMeta}
+	for _, item := range obj.(*v1alpha1.DeviceClaimList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested deviceClaims.
+func (c *FakeDeviceClaims) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(deviceclaimsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a deviceClaim and creates it.  Returns the server's representation of the deviceClaim, and an error, if there is any.
+func (c *FakeDeviceClaims) Create(ctx context.Context, deviceClaim *v1alpha1.DeviceClaim, opts v1.CreateOptions) (result *v1alpha1.DeviceClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(deviceclaimsResource, c.ns, deviceClaim), &v1alpha1.DeviceClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClaim), err
+}
+
+// Update takes the representation of a deviceClaim and updates it. Returns the server's representation of the deviceClaim, and an error, if there is any.
+func (c *FakeDeviceClaims) Update(ctx context.Context, deviceClaim *v1alpha1.DeviceClaim, opts v1.UpdateOptions) (result *v1alpha1.DeviceClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(deviceclaimsResource, c.ns, deviceClaim), &v1alpha1.DeviceClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClaim), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeDeviceClaims) UpdateStatus(ctx context.Context, deviceClaim *v1alpha1.DeviceClaim, opts v1.UpdateOptions) (*v1alpha1.DeviceClaim, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(deviceclaimsResource, "status", c.ns, deviceClaim), &v1alpha1.DeviceClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClaim), err
+}
+
+// Delete takes name of the deviceClaim and deletes it. Returns an error if one occurs.
+func (c *FakeDeviceClaims) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(deviceclaimsResource, c.ns, name, opts), &v1alpha1.DeviceClaim{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeDeviceClaims) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(deviceclaimsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.DeviceClaimList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched deviceClaim.
+func (c *FakeDeviceClaims) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(deviceclaimsResource, c.ns, name, pt, data, subresources...), &v1alpha1.DeviceClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClaim), err
+}
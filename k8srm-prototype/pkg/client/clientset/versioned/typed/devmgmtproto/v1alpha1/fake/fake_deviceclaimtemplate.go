@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeDeviceClaimTemplates implements DeviceClaimTemplateInterface
+type FakeDeviceClaimTemplates struct {
+	Fake *FakeDevmgmtprotoV1alpha1
+	ns   string
+}
+
+var deviceclaimtemplatesResource = schema.GroupVersionResource{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Resource: "deviceclaimtemplates"}
+
+var deviceclaimtemplatesKind = schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DeviceClaimTemplate"}
+
+// Get takes name of the deviceClaimTemplate, and returns the corresponding deviceClaimTemplate object, and an error if there is any.
+func (c *FakeDeviceClaimTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceClaimTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(deviceclaimtemplatesResource, c.ns, name), &v1alpha1.DeviceClaimTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClaimTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of DeviceClaimTemplates that match those selectors.
+func (c *FakeDeviceClaimTemplates) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceClaimTemplateList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(deviceclaimtemplatesResource, deviceclaimtemplatesKind, c.ns, opts), &v1alpha1.DeviceClaimTemplateList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.DeviceClaimTemplateList{ListMeta: obj.(*v1alpha1.DeviceClaimTemplateList).ListMeta}
+	for _, item := range obj.(*v1alpha1.DeviceClaimTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested deviceClaimTemplates.
+func (c *FakeDeviceClaimTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(deviceclaimtemplatesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a deviceClaimTemplate and creates it.  Returns the server's representation of the deviceClaimTemplate, and an error, if there is any.
+func (c *FakeDeviceClaimTemplates) Create(ctx context.Context, deviceClaimTemplate *v1alpha1.DeviceClaimTemplate, opts v1.CreateOptions) (result *v1alpha1.DeviceClaimTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(deviceclaimtemplatesResource, c.ns, deviceClaimTemplate), &v1alpha1.DeviceClaimTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClaimTemplate), err
+}
+
+// Update takes the representation of a deviceClaimTemplate and updates it. Returns the server's representation of the deviceClaimTemplate, and an error, if there is any.
+func (c *FakeDeviceClaimTemplates) Update(ctx context.Context, deviceClaimTemplate *v1alpha1.DeviceClaimTemplate, opts v1.UpdateOptions) (result *v1alpha1.DeviceClaimTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(deviceclaimtemplatesResource, c.ns, deviceClaimTemplate), &v1alpha1.DeviceClaimTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClaimTemplate), err
+}
+
+// Delete takes name of the deviceClaimTemplate and deletes it. Returns an error if one occurs.
+func (c *FakeDeviceClaimTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(deviceclaimtemplatesResource, c.ns, name, opts), &v1alpha1.DeviceClaimTemplate{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeDeviceClaimTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(deviceclaimtemplatesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.DeviceClaimTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched deviceClaimTemplate.
+func (c *FakeDeviceClaimTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceClaimTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(deviceclaimtemplatesResource, c.ns, name, pt, data, subresources...), &v1alpha1.DeviceClaimTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClaimTemplate), err
+}
@@ -0,0 +1,122 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeDeviceDrivers implements DeviceDriverInterface
+type FakeDeviceDrivers struct {
+	Fake *FakeDevmgmtprotoV1alpha1
+}
+
+var devicedriversResource = schema.GroupVersionResource{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Resource: "devicedrivers"}
+
+var devicedriversKind = schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DeviceDriver"}
+
+// Get takes name of the deviceDriver, and returns the corresponding deviceDriver object, and an error if there is any.
+func (c *FakeDeviceDrivers) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceDriver, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(devicedriversResource, name), &v1alpha1.DeviceDriver{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceDriver), err
+}
+
+// List takes label and field selectors, and returns the list of DeviceDrivers that match those selectors.
+func (c *FakeDeviceDrivers) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceDriverList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(devicedriversResource, devicedriversKind, opts), &v1alpha1.DeviceDriverList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.DeviceDriverList{ListMeta: obj.(*v1alpha1.DeviceDriverList).ListMeta}
+	for _, item := range obj.(*v1alpha1.DeviceDriverList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested deviceDrivers.
+func (c *FakeDeviceDrivers) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(devicedriversResource, opts))
+}
+
+// Create takes the representation of a deviceDriver and creates it.  Returns the server's representation of the deviceDriver, and an error, if there is any.
+func (c *FakeDeviceDrivers) Create(ctx context.Context, deviceDriver *v1alpha1.DeviceDriver, opts v1.CreateOptions) (result *v1alpha1.DeviceDriver, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(devicedriversResource, deviceDriver), &v1alpha1.DeviceDriver{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceDriver), err
+}
+
+// Update takes the representation of a deviceDriver and updates it. Returns the server's representation of the deviceDriver, and an error, if there is any.
+func (c *FakeDeviceDrivers) Update(ctx context.Context, deviceDriver *v1alpha1.DeviceDriver, opts v1.UpdateOptions) (result *v1alpha1.DeviceDriver, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(devicedriversResource, deviceDriver), &v1alpha1.DeviceDriver{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceDriver), err
+}
+
+// Delete takes name of the deviceDriver and deletes it. Returns an error if one occurs.
+func (c *FakeDeviceDrivers) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(devicedriversResource, name, opts), &v1alpha1.DeviceDriver{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeDeviceDrivers) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(devicedriversResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.DeviceDriverList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched deviceDriver.
+func (c *FakeDeviceDrivers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceDriver, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(devicedriversResource, name, pt, data, subresources...), &v1alpha1.DeviceDriver{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceDriver), err
+}
@@ -0,0 +1,133 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeDevicePools implements DevicePoolInterface
+type FakeDevicePools struct {
+	Fake *FakeDevmgmtprotoV1alpha1
+}
+
+var devicepoolsResource = schema.GroupVersionResource{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Resource: "devicepools"}
+
+var devicepoolsKind = schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DevicePool"}
+
+// Get takes name of the devicePool, and returns the corresponding devicePool object, and an error if there is any.
+func (c *FakeDevicePools) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DevicePool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(devicepoolsResource, name), &v1alpha1.DevicePool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePool), err
+}
+
+// List takes label and field selectors, and returns the list of DevicePools that match those selectors.
+func (c *FakeDevicePools) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DevicePoolList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(devicepoolsResource, devicepoolsKind, opts), &v1alpha1.DevicePoolList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.DevicePoolList{ListMeta: obj.(*v1alpha1.DevicePoolList).ListMeta}
+	for _, item := range obj.(*v1alpha1.DevicePoolList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested devicePools.
+func (c *FakeDevicePools) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(devicepoolsResource, opts))
+}
+
+// Create takes the representation of a devicePool and creates it.  Returns the server's representation of the devicePool, and an error, if there is any.
+func (c *FakeDevicePools) Create(ctx context.Context, devicePool *v1alpha1.DevicePool, opts v1.CreateOptions) (result *v1alpha1.DevicePool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(devicepoolsResource, devicePool), &v1alpha1.DevicePool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePool), err
+}
+
+// Update takes the representation of a devicePool and updates it. Returns the server's representation of the devicePool, and an error, if there is any.
+func (c *FakeDevicePools) Update(ctx context.Context, devicePool *v1alpha1.DevicePool, opts v1.UpdateOptions) (result *v1alpha1.DevicePool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(devicepoolsResource, devicePool), &v1alpha1.DevicePool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePool), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeDevicePools) UpdateStatus(ctx context.Context, devicePool *v1alpha1.DevicePool, opts v1.UpdateOptions) (*v1alpha1.DevicePool, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(devicepoolsResource, "status", devicePool), &v1alpha1.DevicePool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePool), err
+}
+
+// Delete takes name of the devicePool and deletes it. Returns an error if one occurs.
+func (c *FakeDevicePools) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(devicepoolsResource, name, opts), &v1alpha1.DevicePool{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeDevicePools) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(devicepoolsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.DevicePoolList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched devicePool.
+func (c *FakeDevicePools) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DevicePool, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(devicepoolsResource, name, pt, data, subresources...), &v1alpha1.DevicePool{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePool), err
+}
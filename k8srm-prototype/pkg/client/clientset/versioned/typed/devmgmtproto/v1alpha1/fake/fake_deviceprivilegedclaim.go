@@ -0,0 +1,142 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeDevicePrivilegedClaims implements DevicePrivilegedClaimInterface
+type FakeDevicePrivilegedClaims struct {
+	Fake *FakeDevmgmtprotoV1alpha1
+	ns   string
+}
+
+var deviceprivilegedclaimsResource = schema.GroupVersionResource{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Resource: "deviceprivilegedclaims"}
+
+var deviceprivilegedclaimsKind = schema.GroupVersionKind{Group: "devmgmtproto.k8s.io", Version: "v1alpha1", Kind: "DevicePrivilegedClaim"}
+
+// Get takes name of the devicePrivilegedClaim, and returns the corresponding devicePrivilegedClaim object, and an error if there is any.
+func (c *FakeDevicePrivilegedClaims) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DevicePrivilegedClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(deviceprivilegedclaimsResource, c.ns, name), &v1alpha1.DevicePrivilegedClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePrivilegedClaim), err
+}
+
+// List takes label and field selectors, and returns the list of DevicePrivilegedClaims that match those selectors.
+func (c *FakeDevicePrivilegedClaims) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DevicePrivilegedClaimList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(deviceprivilegedclaimsResource, deviceprivilegedclaimsKind, c.ns, opts), &v1alpha1.DevicePrivilegedClaimList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.DevicePrivilegedClaimList{ListMeta: obj.(*v1alpha1.DevicePrivilegedClaimList).ListMeta}
+	for _, item := range obj.(*v1alpha1.DevicePrivilegedClaimList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested devicePrivilegedClaims.
+func (c *FakeDevicePrivilegedClaims) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(deviceprivilegedclaimsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a devicePrivilegedClaim and creates it.  Returns the server's representation of the devicePrivilegedClaim, and an error, if there is any.
+func (c *FakeDevicePrivilegedClaims) Create(ctx context.Context, devicePrivilegedClaim *v1alpha1.DevicePrivilegedClaim, opts v1.CreateOptions) (result *v1alpha1.DevicePrivilegedClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(deviceprivilegedclaimsResource, c.ns, devicePrivilegedClaim), &v1alpha1.DevicePrivilegedClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePrivilegedClaim), err
+}
+
+// Update takes the representation of a devicePrivilegedClaim and updates it. Returns the server's representation of the devicePrivilegedClaim, and an error, if there is any.
+func (c *FakeDevicePrivilegedClaims) Update(ctx context.Context, devicePrivilegedClaim *v1alpha1.DevicePrivilegedClaim, opts v1.UpdateOptions) (result *v1alpha1.DevicePrivilegedClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(deviceprivilegedclaimsResource, c.ns, devicePrivilegedClaim), &v1alpha1.DevicePrivilegedClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePrivilegedClaim), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeDevicePrivilegedClaims) UpdateStatus(ctx context.Context, devicePrivilegedClaim *v1alpha1.DevicePrivilegedClaim, opts v1.UpdateOptions) (*v1alpha1.DevicePrivilegedClaim, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(deviceprivilegedclaimsResource, "status", c.ns, devicePrivilegedClaim), &v1alpha1.DevicePrivilegedClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePrivilegedClaim), err
+}
+
+// Delete takes name of the devicePrivilegedClaim and deletes it. Returns an error if one occurs.
+func (c *FakeDevicePrivilegedClaims) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(deviceprivilegedclaimsResource, c.ns, name, opts), &v1alpha1.DevicePrivilegedClaim{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeDevicePrivilegedClaims) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(deviceprivilegedclaimsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.DevicePrivilegedClaimList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched devicePrivilegedClaim.
+func (c *FakeDevicePrivilegedClaims) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DevicePrivilegedClaim, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(deviceprivilegedclaimsResource, c.ns, name, pt, data, subresources...), &v1alpha1.DevicePrivilegedClaim{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DevicePrivilegedClaim), err
+}
@@ -0,0 +1,64 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned/typed/devmgmtproto/v1alpha1"
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+)
+
+type FakeDevmgmtprotoV1alpha1 struct {
+	*testing.Fake
+}
+
+func (c *FakeDevmgmtprotoV1alpha1) DeviceClaims(namespace string) v1alpha1.DeviceClaimInterface {
+	return &FakeDeviceClaims{c, namespace}
+}
+
+func (c *FakeDevmgmtprotoV1alpha1) DeviceClaimTemplates(namespace string) v1alpha1.DeviceClaimTemplateInterface {
+	return &FakeDeviceClaimTemplates{c, namespace}
+}
+
+func (c *FakeDevmgmtprotoV1alpha1) DeviceClasses() v1alpha1.DeviceClassInterface {
+	return &FakeDeviceClasses{c}
+}
+
+func (c *FakeDevmgmtprotoV1alpha1) DeviceDrivers() v1alpha1.DeviceDriverInterface {
+	return &FakeDeviceDrivers{c}
+}
+
+func (c *FakeDevmgmtprotoV1alpha1) DevicePools() v1alpha1.DevicePoolInterface {
+	return &FakeDevicePools{c}
+}
+
+func (c *FakeDevmgmtprotoV1alpha1) DevicePrivilegedClaims(namespace string) v1alpha1.DevicePrivilegedClaimInterface {
+	return &FakeDevicePrivilegedClaims{c, namespace}
+}
+
+func (c *FakeDevmgmtprotoV1alpha1) DeviceSetClaims(namespace string) v1alpha1.DeviceSetClaimInterface {
+	return &FakeDeviceSetClaims{c, namespace}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeDevmgmtprotoV1alpha1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
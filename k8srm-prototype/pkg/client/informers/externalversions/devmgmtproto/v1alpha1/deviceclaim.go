@@ -0,0 +1,90 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	time "time"
+
+	devmgmtprotov1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	versioned "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/listers/devmgmtproto/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// DeviceClaimInformer provides access to a shared informer and lister for
+// DeviceClaims.
+type DeviceClaimInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.DeviceClaimLister
+}
+
+type deviceClaimInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewDeviceClaimInformer constructs a new informer for DeviceClaim type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewDeviceClaimInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredDeviceClaimInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredDeviceClaimInformer constructs a new informer for DeviceClaim type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredDeviceClaimInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.DevmgmtprotoV1alpha1().DeviceClaims(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.DevmgmtprotoV1alpha1().DeviceClaims(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&devmgmtprotov1alpha1.DeviceClaim{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *deviceClaimInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredDeviceClaimInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *deviceClaimInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&devmgmtprotov1alpha1.DeviceClaim{}, f.defaultInformer)
+}
+
+func (f *deviceClaimInformer) Lister() v1alpha1.DeviceClaimLister {
+	return v1alpha1.NewDeviceClaimLister(f.Informer().GetIndexer())
+}
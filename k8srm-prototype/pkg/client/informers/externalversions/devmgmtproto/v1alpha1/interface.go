@@ -0,0 +1,87 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	internalinterfaces "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// DeviceClaims returns a DeviceClaimInformer.
+	DeviceClaims() DeviceClaimInformer
+	// DeviceClaimTemplates returns a DeviceClaimTemplateInformer.
+	DeviceClaimTemplates() DeviceClaimTemplateInformer
+	// DeviceClasses returns a DeviceClassInformer.
+	DeviceClasses() DeviceClassInformer
+	// DeviceDrivers returns a DeviceDriverInformer.
+	DeviceDrivers() DeviceDriverInformer
+	// DevicePools returns a DevicePoolInformer.
+	DevicePools() DevicePoolInformer
+	// DevicePrivilegedClaims returns a DevicePrivilegedClaimInformer.
+	DevicePrivilegedClaims() DevicePrivilegedClaimInformer
+	// DeviceSetClaims returns a DeviceSetClaimInformer.
+	DeviceSetClaims() DeviceSetClaimInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// DeviceClaims returns a DeviceClaimInformer.
+func (v *version) DeviceClaims() DeviceClaimInformer {
+	return &deviceClaimInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DeviceClaimTemplates returns a DeviceClaimTemplateInformer.
+func (v *version) DeviceClaimTemplates() DeviceClaimTemplateInformer {
+	return &deviceClaimTemplateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DeviceClasses returns a DeviceClassInformer.
+func (v *version) DeviceClasses() DeviceClassInformer {
+	return &deviceClassInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// DeviceDrivers returns a DeviceDriverInformer.
+func (v *version) DeviceDrivers() DeviceDriverInformer {
+	return &deviceDriverInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// DevicePools returns a DevicePoolInformer.
+func (v *version) DevicePools() DevicePoolInformer {
+	return &devicePoolInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// DevicePrivilegedClaims returns a DevicePrivilegedClaimInformer.
+func (v *version) DevicePrivilegedClaims() DevicePrivilegedClaimInformer {
+	return &devicePrivilegedClaimInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DeviceSetClaims returns a DeviceSetClaimInformer.
+func (v *version) DeviceSetClaims() DeviceSetClaimInformer {
+	return &deviceSetClaimInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DeviceClaimLister helps list DeviceClaims.
+// All objects returned here must be treated as read-only.
+type DeviceClaimLister interface {
+	// List lists all DeviceClaims in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceClaim, err error)
+	// DeviceClaims returns an object that can list and get DeviceClaims.
+	DeviceClaims(namespace string) DeviceClaimNamespaceLister
+	DeviceClaimListerExpansion
+}
+
+// deviceClaimLister implements the DeviceClaimLister interface.
+type deviceClaimLister struct {
+	indexer cache.Indexer
+}
+
+// NewDeviceClaimLister returns a new DeviceClaimLister.
+func NewDeviceClaimLister(indexer cache.Indexer) DeviceClaimLister {
+	return &deviceClaimLister{indexer: indexer}
+}
+
+// List lists all DeviceClaims in the indexer.
+func (s *deviceClaimLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceClaim, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceClaim))
+	})
+	return ret, err
+}
+
+// DeviceClaims returns an object that can list and get DeviceClaims.
+func (s *deviceClaimLister) DeviceClaims(namespace string) DeviceClaimNamespaceLister {
+	return deviceClaimNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// DeviceClaimNamespaceLister helps list and get DeviceClaims.
+// All objects returned here must be treated as read-only.
+type DeviceClaimNamespaceLister interface {
+	// List lists all DeviceClaims in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceClaim, err error)
+	// Get retrieves the DeviceClaim from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.DeviceClaim, error)
+	DeviceClaimNamespaceListerExpansion
+}
+
+// deviceClaimNamespaceLister implements the DeviceClaimNamespaceLister
+// interface.
+type deviceClaimNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all DeviceClaims in the indexer for a given namespace.
+func (s deviceClaimNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceClaim, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceClaim))
+	})
+	return ret, err
+}
+
+// Get retrieves the DeviceClaim from the indexer for a given namespace and name.
+func (s deviceClaimNamespaceLister) Get(name string) (*v1alpha1.DeviceClaim, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("deviceclaim"), name)
+	}
+	return obj.(*v1alpha1.DeviceClaim), nil
+}
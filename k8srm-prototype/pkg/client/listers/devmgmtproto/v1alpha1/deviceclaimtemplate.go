@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DeviceClaimTemplateLister helps list DeviceClaimTemplates.
+// All objects returned here must be treated as read-only.
+type DeviceClaimTemplateLister interface {
+	// List lists all DeviceClaimTemplates in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceClaimTemplate, err error)
+	// DeviceClaimTemplates returns an object that can list and get DeviceClaimTemplates.
+	DeviceClaimTemplates(namespace string) DeviceClaimTemplateNamespaceLister
+	DeviceClaimTemplateListerExpansion
+}
+
+// deviceClaimTemplateLister implements the DeviceClaimTemplateLister interface.
+type deviceClaimTemplateLister struct {
+	indexer cache.Indexer
+}
+
+// NewDeviceClaimTemplateLister returns a new DeviceClaimTemplateLister.
+func NewDeviceClaimTemplateLister(indexer cache.Indexer) DeviceClaimTemplateLister {
+	return &deviceClaimTemplateLister{indexer: indexer}
+}
+
+// List lists all DeviceClaimTemplates in the indexer.
+func (s *deviceClaimTemplateLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceClaimTemplate, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceClaimTemplate))
+	})
+	return ret, err
+}
+
+// DeviceClaimTemplates returns an object that can list and get DeviceClaimTemplates.
+func (s *deviceClaimTemplateLister) DeviceClaimTemplates(namespace string) DeviceClaimTemplateNamespaceLister {
+	return deviceClaimTemplateNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// DeviceClaimTemplateNamespaceLister helps list and get DeviceClaimTemplates.
+// All objects returned here must be treated as read-only.
+type DeviceClaimTemplateNamespaceLister interface {
+	// List lists all DeviceClaimTemplates in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceClaimTemplate, err error)
+	// Get retrieves the DeviceClaimTemplate from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.DeviceClaimTemplate, error)
+	DeviceClaimTemplateNamespaceListerExpansion
+}
+
+// deviceClaimTemplateNamespaceLister implements the DeviceClaimTemplateNamespaceLister
+// interface.
+type deviceClaimTemplateNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all DeviceClaimTemplates in the indexer for a given namespace.
+func (s deviceClaimTemplateNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceClaimTemplate, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceClaimTemplate))
+	})
+	return ret, err
+}
+
+// Get retrieves the DeviceClaimTemplate from the indexer for a given namespace and name.
+func (s deviceClaimTemplateNamespaceLister) Get(name string) (*v1alpha1.DeviceClaimTemplate, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("deviceclaimtemplate"), name)
+	}
+	return obj.(*v1alpha1.DeviceClaimTemplate), nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DeviceDriverLister helps list DeviceDrivers.
+// All objects returned here must be treated as read-only.
+type DeviceDriverLister interface {
+	// List lists all DeviceDrivers in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceDriver, err error)
+	// Get retrieves the DeviceDriver from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.DeviceDriver, error)
+	DeviceDriverListerExpansion
+}
+
+// deviceDriverLister implements the DeviceDriverLister interface.
+type deviceDriverLister struct {
+	indexer cache.Indexer
+}
+
+// NewDeviceDriverLister returns a new DeviceDriverLister.
+func NewDeviceDriverLister(indexer cache.Indexer) DeviceDriverLister {
+	return &deviceDriverLister{indexer: indexer}
+}
+
+// List lists all DeviceDrivers in the indexer.
+func (s *deviceDriverLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceDriver, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceDriver))
+	})
+	return ret, err
+}
+
+// Get retrieves the DeviceDriver from the index for a given name.
+func (s *deviceDriverLister) Get(name string) (*v1alpha1.DeviceDriver, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("devicedriver"), name)
+	}
+	return obj.(*v1alpha1.DeviceDriver), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DevicePrivilegedClaimLister helps list DevicePrivilegedClaims.
+// All objects returned here must be treated as read-only.
+type DevicePrivilegedClaimLister interface {
+	// List lists all DevicePrivilegedClaims in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DevicePrivilegedClaim, err error)
+	// DevicePrivilegedClaims returns an object that can list and get DevicePrivilegedClaims.
+	DevicePrivilegedClaims(namespace string) DevicePrivilegedClaimNamespaceLister
+	DevicePrivilegedClaimListerExpansion
+}
+
+// devicePrivilegedClaimLister implements the DevicePrivilegedClaimLister interface.
+type devicePrivilegedClaimLister struct {
+	indexer cache.Indexer
+}
+
+// NewDevicePrivilegedClaimLister returns a new DevicePrivilegedClaimLister.
+func NewDevicePrivilegedClaimLister(indexer cache.Indexer) DevicePrivilegedClaimLister {
+	return &devicePrivilegedClaimLister{indexer: indexer}
+}
+
+// List lists all DevicePrivilegedClaims in the indexer.
+func (s *devicePrivilegedClaimLister) List(selector labels.Selector) (ret []*v1alpha1.DevicePrivilegedClaim, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DevicePrivilegedClaim))
+	})
+	return ret, err
+}
+
+// DevicePrivilegedClaims returns an object that can list and get DevicePrivilegedClaims.
+func (s *devicePrivilegedClaimLister) DevicePrivilegedClaims(namespace string) DevicePrivilegedClaimNamespaceLister {
+	return devicePrivilegedClaimNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// DevicePrivilegedClaimNamespaceLister helps list and get DevicePrivilegedClaims.
+// All objects returned here must be treated as read-only.
+type DevicePrivilegedClaimNamespaceLister interface {
+	// List lists all DevicePrivilegedClaims in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DevicePrivilegedClaim, err error)
+	// Get retrieves the DevicePrivilegedClaim from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.DevicePrivilegedClaim, error)
+	DevicePrivilegedClaimNamespaceListerExpansion
+}
+
+// devicePrivilegedClaimNamespaceLister implements the DevicePrivilegedClaimNamespaceLister
+// interface.
+type devicePrivilegedClaimNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all DevicePrivilegedClaims in the indexer for a given namespace.
+func (s devicePrivilegedClaimNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.DevicePrivilegedClaim, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DevicePrivilegedClaim))
+	})
+	return ret, err
+}
+
+// Get retrieves the DevicePrivilegedClaim from the indexer for a given namespace and name.
+func (s devicePrivilegedClaimNamespaceLister) Get(name string) (*v1alpha1.DevicePrivilegedClaim, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("deviceprivilegedclaim"), name)
+	}
+	return obj.(*v1alpha1.DevicePrivilegedClaim), nil
+}
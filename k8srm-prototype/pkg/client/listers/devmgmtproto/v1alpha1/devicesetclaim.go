@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DeviceSetClaimLister helps list DeviceSetClaims.
+// All objects returned here must be treated as read-only.
+type DeviceSetClaimLister interface {
+	// List lists all DeviceSetClaims in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceSetClaim, err error)
+	// DeviceSetClaims returns an object that can list and get DeviceSetClaims.
+	DeviceSetClaims(namespace string) DeviceSetClaimNamespaceLister
+	DeviceSetClaimListerExpansion
+}
+
+// deviceSetClaimLister implements the DeviceSetClaimLister interface.
+type deviceSetClaimLister struct {
+	indexer cache.Indexer
+}
+
+// NewDeviceSetClaimLister returns a new DeviceSetClaimLister.
+func NewDeviceSetClaimLister(indexer cache.Indexer) DeviceSetClaimLister {
+	return &deviceSetClaimLister{indexer: indexer}
+}
+
+// List lists all DeviceSetClaims in the indexer.
+func (s *deviceSetClaimLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceSetClaim, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceSetClaim))
+	})
+	return ret, err
+}
+
+// DeviceSetClaims returns an object that can list and get DeviceSetClaims.
+func (s *deviceSetClaimLister) DeviceSetClaims(namespace string) DeviceSetClaimNamespaceLister {
+	return deviceSetClaimNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// DeviceSetClaimNamespaceLister helps list and get DeviceSetClaims.
+// All objects returned here must be treated as read-only.
+type DeviceSetClaimNamespaceLister interface {
+	// List lists all DeviceSetClaims in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceSetClaim, err error)
+	// Get retrieves the DeviceSetClaim from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.DeviceSetClaim, error)
+	DeviceSetClaimNamespaceListerExpansion
+}
+
+// deviceSetClaimNamespaceLister implements the DeviceSetClaimNamespaceLister
+// interface.
+type deviceSetClaimNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all DeviceSetClaims in the indexer for a given namespace.
+func (s deviceSetClaimNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceSetClaim, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceSetClaim))
+	})
+	return ret, err
+}
+
+// Get retrieves the DeviceSetClaim from the indexer for a given namespace and name.
+func (s deviceSetClaimNamespaceLister) Get(name string) (*v1alpha1.DeviceSetClaim, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("devicesetclaim"), name)
+	}
+	return obj.(*v1alpha1.DeviceSetClaim), nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// DeviceClaimListerExpansion allows custom methods to be added to
+// DeviceClaimLister.
+type DeviceClaimListerExpansion interface{}
+
+// DeviceClaimNamespaceListerExpansion allows custom methods to be added to
+// DeviceClaimNamespaceLister.
+type DeviceClaimNamespaceListerExpansion interface{}
+
+// DeviceClaimTemplateListerExpansion allows custom methods to be added to
+// DeviceClaimTemplateLister.
+type DeviceClaimTemplateListerExpansion interface{}
+
+// DeviceClaimTemplateNamespaceListerExpansion allows custom methods to be added to
+// DeviceClaimTemplateNamespaceLister.
+type DeviceClaimTemplateNamespaceListerExpansion interface{}
+
+// DeviceClassListerExpansion allows custom methods to be added to
+// DeviceClassLister.
+type DeviceClassListerExpansion interface{}
+
+// DeviceDriverListerExpansion allows custom methods to be added to
+// DeviceDriverLister.
+type DeviceDriverListerExpansion interface{}
+
+// DevicePoolListerExpansion allows custom methods to be added to
+// DevicePoolLister.
+type DevicePoolListerExpansion interface{}
+
+// DevicePrivilegedClaimListerExpansion allows custom methods to be added to
+// DevicePrivilegedClaimLister.
+type DevicePrivilegedClaimListerExpansion interface{}
+
+// DevicePrivilegedClaimNamespaceListerExpansion allows custom methods to be added to
+// DevicePrivilegedClaimNamespaceLister.
+type DevicePrivilegedClaimNamespaceListerExpansion interface{}
+
+// DeviceSetClaimListerExpansion allows custom methods to be added to
+// DeviceSetClaimLister.
+type DeviceSetClaimListerExpansion interface{}
+
+// DeviceSetClaimNamespaceListerExpansion allows custom methods to be added to
+// DeviceSetClaimNamespaceLister.
+type DeviceSetClaimNamespaceListerExpansion interface{}
@@ -0,0 +1,403 @@
+// Package claimtemplate implements the claim template controller. For each
+// device claim of a Pod that refers to a DeviceClaimTemplate, it generates a
+// DeviceClaim, DevicePrivilegedClaim, or DeviceSetClaim from the template,
+// depending on which spec the template has. The generated claims are named
+// after the Pod and its device claim, and are controlled by the Pod.
+//
+// Templates are validated before claims are generated from them, and a
+// device claim of a Pod with an invalid template is skipped until the template
+// is fixed. The scheduler schedules the generated claims of all kinds.
+//
+// There is no garbage collector in the mock API server, so the controller also
+// deletes the claims that are controlled by Pods that no longer exist. This
+// includes the claims that the scheduler creates for embedded claims.
+package claimtemplate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api/validation"
+	devmgmtinformers "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/informers/externalversions/devmgmtproto/v1alpha1"
+	devmgmtlisters "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/listers/devmgmtproto/v1alpha1"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/podscheduler"
+)
+
+// claimKinds are the kinds of claims that can be generated from a template,
+// and claimResources their resources. The dynamic client and informers are
+// used for them, so that they can be handled alike.
+var (
+	claimKinds     = []string{"DeviceClaim", "DevicePrivilegedClaim", "DeviceSetClaim"}
+	claimResources = map[string]schema.GroupVersionResource{
+		"DeviceClaim":           api.SchemeGroupVersion.WithResource("deviceclaims"),
+		"DevicePrivilegedClaim": api.SchemeGroupVersion.WithResource("deviceprivilegedclaims"),
+		"DeviceSetClaim":        api.SchemeGroupVersion.WithResource("devicesetclaims"),
+	}
+
+	podGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+)
+
+// podIndex is the name of the claim index by the namespace and name of the Pod
+// that controls the claim.
+const podIndex = "pod"
+
+// Controller generates claims for Pods from DeviceClaimTemplates, and deletes
+// them when the Pods are deleted. Pods are queued by namespace and name.
+type Controller struct {
+	client    dynamic.Interface
+	pods      cache.GenericLister
+	claims    map[string]cache.SharedIndexInformer
+	templates devmgmtlisters.DeviceClaimTemplateLister
+	synced    []cache.InformerSynced
+	queue     workqueue.RateLimitingInterface
+}
+
+// NewController returns a Controller that gets DeviceClaimTemplates from the
+// informer, and Pods and the claims of each kind from dynamic informers of the
+// factory. Pods are unstructured, since they have fields that are not part of
+// the real PodSpec. The informers must be started by the caller.
+func NewController(client dynamic.Interface, factory dynamicinformer.DynamicSharedInformerFactory, templateInformer devmgmtinformers.DeviceClaimTemplateInformer) (*Controller, error) {
+	podInformer := factory.ForResource(podGVR)
+	c := &Controller{
+		client:    client,
+		pods:      podInformer.Lister(),
+		claims:    make(map[string]cache.SharedIndexInformer),
+		templates: templateInformer.Lister(),
+		synced:    []cache.InformerSynced{podInformer.Informer().HasSynced, templateInformer.Informer().HasSynced},
+		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "claimtemplate"),
+	}
+
+	if _, err := podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueuePod,
+		UpdateFunc: func(_, obj any) { c.enqueuePod(obj) },
+		DeleteFunc: c.enqueuePod,
+	}); err != nil {
+		return nil, err
+	}
+
+	// The Pods that control claims are queued when the claims change, so
+	// that deleted claims are generated again. This includes the initial
+	// list, so the claims of Pods that were deleted while the controller
+	// was not running are deleted at startup.
+	for _, kind := range claimKinds {
+		informer := factory.ForResource(claimResources[kind]).Informer()
+		if err := informer.AddIndexers(cache.Indexers{podIndex: claimPodIndexFunc}); err != nil {
+			return nil, err
+		}
+
+		if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.enqueueClaimPod,
+			UpdateFunc: func(_, obj any) { c.enqueueClaimPod(obj) },
+			DeleteFunc: c.enqueueClaimPod,
+		}); err != nil {
+			return nil, err
+		}
+
+		c.claims[kind] = informer
+		c.synced = append(c.synced, informer.HasSynced)
+	}
+
+	// A Pod may be created before the template it refers to.
+	if _, err := templateInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueTemplatePods,
+		UpdateFunc: func(_, obj any) { c.enqueueTemplatePods(obj) },
+	}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Controller) enqueuePod(obj any) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	c.queue.Add(key)
+}
+
+// enqueueClaimPod queues the Pod that controls the claim, if any.
+func (c *Controller) enqueueClaimPod(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	claim, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	if ref := podControllerOf(claim); ref != nil {
+		c.queue.Add(claim.GetNamespace() + "/" + ref.Name)
+	}
+}
+
+// claimPodIndexFunc indexes the claims by the namespace and name of the Pod
+// that controls them, which is also the key of the Pod in the queue.
+func claimPodIndexFunc(obj any) ([]string, error) {
+	claim, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+
+	ref := podControllerOf(claim)
+	if ref == nil {
+		return nil, nil
+	}
+
+	return []string{claim.GetNamespace() + "/" + ref.Name}, nil
+}
+
+// enqueueTemplatePods queues all Pods in the namespace of the template, since
+// there is no index of the templates that Pods refer to.
+func (c *Controller) enqueueTemplatePods(obj any) {
+	template, ok := obj.(*api.DeviceClaimTemplate)
+	if !ok {
+		return
+	}
+
+	pods, err := c.pods.ByNamespace(template.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	for _, pod := range pods {
+		c.enqueuePod(pod)
+	}
+}
+
+// Run runs the controller with the given number of workers until the context
+// is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Info("starting claim template controller")
+	defer klog.Info("shutting down claim template controller")
+
+	if !cache.WaitForCacheSync(ctx.Done(), c.synced...) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.syncPod(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("error syncing pod %s: %w", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+// syncPod deletes the claims of earlier Pods with the same name as the Pod,
+// and generates the missing claims of the Pod, if it still exists.
+func (c *Controller) syncPod(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	var pod *unstructured.Unstructured
+	obj, err := c.pods.ByNamespace(namespace).Get(name)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		pod = obj.(*unstructured.Unstructured)
+	}
+
+	if err := c.deleteOrphanedClaims(ctx, key, pod); err != nil {
+		return err
+	}
+
+	if pod == nil || pod.GetDeletionTimestamp() != nil {
+		return nil
+	}
+
+	podClaims, err := podscheduler.PodDeviceClaims(pod)
+	if err != nil {
+		// The Pod will not get any better by trying again.
+		klog.Errorf("skipping pod %s: %v", key, err)
+		return nil
+	}
+
+	for _, pdc := range podClaims {
+		if pdc.DeviceClaimTemplateName == nil {
+			continue
+		}
+
+		template, err := c.templates.DeviceClaimTemplates(namespace).Get(*pdc.DeviceClaimTemplateName)
+		if err != nil {
+			return fmt.Errorf("error getting device claim template %s/%s: %w", namespace, *pdc.DeviceClaimTemplateName, err)
+		}
+
+		// The Pod is queued again when the template is updated.
+		if errs := validation.ValidateDeviceClaimTemplateSpec(&template.Spec, field.NewPath("spec")); len(errs) > 0 {
+			klog.Errorf("skipping device claim %s of pod %s: invalid device claim template %s/%s: %v", pdc.Name, key, namespace, template.Name, errs.ToAggregate())
+			continue
+		}
+
+		if err := c.createClaim(ctx, pod, newClaim(pod, pdc.Name, template)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteOrphanedClaims deletes the claims controlled by a Pod with the key
+// that is not the given Pod, which is nil if there is no such Pod. The claims
+// are found in the informer caches.
+func (c *Controller) deleteOrphanedClaims(ctx context.Context, key string, pod *unstructured.Unstructured) error {
+	for _, kind := range claimKinds {
+		objs, err := c.claims[kind].GetIndexer().ByIndex(podIndex, key)
+		if err != nil {
+			return fmt.Errorf("error listing %s: %w", claimResources[kind].Resource, err)
+		}
+
+		for _, obj := range objs {
+			claim := obj.(*unstructured.Unstructured)
+			ref := podControllerOf(claim)
+			if pod != nil && ref.UID == pod.GetUID() {
+				continue
+			}
+
+			// The UID precondition keeps a claim that was just
+			// replaced from being deleted.
+			err := c.client.Resource(claimResources[kind]).Namespace(claim.GetNamespace()).Delete(ctx, claim.GetName(), metav1.DeleteOptions{
+				Preconditions: metav1.NewUIDPreconditions(string(claim.GetUID())),
+			})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("error deleting %s %s/%s: %w", kind, claim.GetNamespace(), claim.GetName(), err)
+			}
+			klog.Infof("deleted %s %s/%s of pod %s", kind, claim.GetNamespace(), claim.GetName(), ref.Name)
+		}
+	}
+
+	return nil
+}
+
+// newClaim returns the claim for the Pod device claim, of the kind of the
+// spec in the template.
+func newClaim(pod *unstructured.Unstructured, claimName string, template *api.DeviceClaimTemplate) runtime.Object {
+	meta := metav1.ObjectMeta{
+		Name:            api.PodClaimName(pod.GetName(), claimName),
+		Namespace:       pod.GetNamespace(),
+		Labels:          template.Spec.Labels,
+		Annotations:     template.Spec.Annotations,
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(pod, pod.GroupVersionKind())},
+	}
+
+	spec := template.Spec.DeepCopy()
+	switch {
+	case spec.DevicePrivilegedClaimSpec != nil:
+		return &api.DevicePrivilegedClaim{
+			TypeMeta:   metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DevicePrivilegedClaim"},
+			ObjectMeta: meta,
+			Spec:       *spec.DevicePrivilegedClaimSpec,
+		}
+	case spec.DeviceSetClaimSpec != nil:
+		return &api.DeviceSetClaim{
+			TypeMeta:   metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DeviceSetClaim"},
+			ObjectMeta: meta,
+			Spec:       *spec.DeviceSetClaimSpec,
+		}
+	}
+
+	// Templates are validated to have exactly one spec.
+	claim := &api.DeviceClaim{
+		TypeMeta:   metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DeviceClaim"},
+		ObjectMeta: meta,
+	}
+	if spec.DeviceClaimSpec != nil {
+		claim.Spec = *spec.DeviceClaimSpec
+	}
+
+	return claim
+}
+
+// createClaim creates the claim, unless it already exists. An existing claim
+// is left as it is, as the claims of a Pod do not change along with their
+// template, but it must be controlled by the Pod.
+//
+// The claim is looked up in the informer cache. A claim that exists but is not
+// in the cache yet makes the create fail, and the Pod is tried again once the
+// cache has caught up.
+func (c *Controller) createClaim(ctx context.Context, pod *unstructured.Unstructured, claim runtime.Object) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(claim)
+	if err != nil {
+		return err
+	}
+	obj := &unstructured.Unstructured{Object: u}
+	id := fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+
+	existing, exists, err := c.claims[obj.GetKind()].GetIndexer().GetByKey(obj.GetNamespace() + "/" + obj.GetName())
+	if err != nil {
+		return fmt.Errorf("error getting %s: %w", id, err)
+	}
+
+	if !exists {
+		if _, err := c.client.Resource(claimResources[obj.GetKind()]).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating %s: %w", id, err)
+		}
+		klog.Infof("created %s for pod %s", id, pod.GetName())
+		return nil
+	}
+
+	if !metav1.IsControlledBy(existing.(*unstructured.Unstructured), pod) {
+		return fmt.Errorf("%s already exists and is not controlled by pod %s", id, pod.GetName())
+	}
+
+	return nil
+}
+
+// podControllerOf returns the controller reference of the claim if its
+// controller is a Pod.
+func podControllerOf(claim *unstructured.Unstructured) *metav1.OwnerReference {
+	ref := metav1.GetControllerOf(claim)
+	if ref == nil || ref.APIVersion != "v1" || ref.Kind != "Pod" {
+		return nil
+	}
+
+	return ref
+}
@@ -0,0 +1,267 @@
+package claimtemplate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/informers/externalversions"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/harness"
+)
+
+// startController runs the controller against the harness until the test
+// ends.
+func startController(t *testing.T, h *harness.Harness) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	dynamicFactory := dynamicinformer.NewDynamicSharedInformerFactory(h.Clients.Dynamic, 0)
+	factory := externalversions.NewSharedInformerFactory(h.Clients.DevMgmt, 0)
+
+	c, err := NewController(h.Clients.Dynamic, dynamicFactory, factory.Devmgmtproto().V1alpha1().DeviceClaimTemplates())
+	require.NoError(t, err)
+
+	dynamicFactory.Start(ctx.Done())
+	factory.Start(ctx.Done())
+	go c.Run(ctx, 1)
+}
+
+func newPod(name string, templates ...string) *unstructured.Unstructured {
+	var deviceClaims []any
+	for _, template := range templates {
+		deviceClaims = append(deviceClaims, map[string]any{"name": template, "claimTemplateName": template})
+	}
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"spec": map[string]any{
+			"containers":   []any{map[string]any{"name": "my-container", "image": "registry.k8s.io/pause:3.6"}},
+			"deviceClaims": deviceClaims,
+		},
+	}}
+}
+
+func newTemplate(name string, spec api.DeviceClaimTemplateSpec) *api.DeviceClaimTemplate {
+	return &api.DeviceClaimTemplate{
+		TypeMeta:   metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DeviceClaimTemplate"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       spec,
+	}
+}
+
+// getClaim returns the claim, or nil if it does not exist.
+func getClaim(t *testing.T, h *harness.Harness, kind, name string) *unstructured.Unstructured {
+	claim, err := h.Clients.Dynamic.Resource(claimResources[kind]).Namespace("default").Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	require.NoError(t, err)
+
+	return claim
+}
+
+func TestController(t *testing.T) {
+	h := harness.New(t)
+	startController(t, h)
+
+	h.Add(
+		newTemplate("gpu", api.DeviceClaimTemplateSpec{
+			ObjectMeta:      metav1.ObjectMeta{Labels: map[string]string{"example.com/mode": "superfast"}},
+			DeviceClaimSpec: &api.DeviceClaimSpec{DeviceClass: "gpu"},
+		}),
+		newTemplate("privileged", api.DeviceClaimTemplateSpec{
+			DevicePrivilegedClaimSpec: &api.DevicePrivilegedClaimSpec{Driver: "example.com-foozer"},
+		}),
+		newTemplate("set", api.DeviceClaimTemplateSpec{
+			DeviceSetClaimSpec: &api.DeviceSetClaimSpec{
+				MatchAttributes: []string{"numa"},
+				ClaimSpec:       []api.DeviceClaimSpec{{DeviceClass: "gpu"}, {DeviceClass: "sriov-nic"}},
+			},
+		}),
+		newPod("my-pod", "gpu", "privileged", "set"),
+	)
+
+	testCases := map[string]struct {
+		kind string
+		name string
+		spec any
+	}{
+		"claim": {
+			kind: "DeviceClaim",
+			name: api.PodClaimName("my-pod", "gpu"),
+			spec: map[string]any{"deviceClass": "gpu"},
+		},
+		"privileged claim": {
+			kind: "DevicePrivilegedClaim",
+			name: api.PodClaimName("my-pod", "privileged"),
+			spec: map[string]any{"driver": "example.com-foozer"},
+		},
+		"set claim": {
+			kind: "DeviceSetClaim",
+			name: api.PodClaimName("my-pod", "set"),
+			spec: map[string]any{
+				"matchAttributes": []any{"numa"},
+				"claimSpec":       []any{map[string]any{"deviceClass": "gpu"}, map[string]any{"deviceClass": "sriov-nic"}},
+			},
+		},
+	}
+
+	pod := h.Pod("default", "my-pod")
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.Eventually(t, func() bool {
+				return getClaim(t, h, tc.kind, tc.name) != nil
+			}, 10*time.Second, 10*time.Millisecond)
+
+			claim := getClaim(t, h, tc.kind, tc.name)
+			require.Equal(t, tc.spec, claim.Object["spec"])
+			require.True(t, metav1.IsControlledBy(claim, pod))
+		})
+	}
+
+	// Labels and annotations are copied from the template.
+	require.Equal(t, map[string]string{"example.com/mode": "superfast"}, getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu")).GetLabels())
+
+	// The claims are deleted along with the Pod.
+	require.NoError(t, h.Clients.Dynamic.Resource(podGVR).Namespace("default").Delete(context.Background(), "my-pod", metav1.DeleteOptions{}))
+	for _, tc := range testCases {
+		require.Eventually(t, func() bool {
+			return getClaim(t, h, tc.kind, tc.name) == nil
+		}, 10*time.Second, 10*time.Millisecond, "%s %s was not deleted", tc.kind, tc.name)
+	}
+}
+
+func TestControllerLateTemplate(t *testing.T) {
+	h := harness.New(t)
+	startController(t, h)
+
+	// The claim is generated once the template exists.
+	h.Add(newPod("my-pod", "gpu"))
+	time.Sleep(100 * time.Millisecond)
+	require.Nil(t, getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu")))
+
+	h.Add(newTemplate("gpu", api.DeviceClaimTemplateSpec{
+		DeviceClaimSpec: &api.DeviceClaimSpec{DeviceClass: "gpu"},
+	}))
+	require.Eventually(t, func() bool {
+		return getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu")) != nil
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestControllerRecreatedPod(t *testing.T) {
+	h := harness.New(t)
+	startController(t, h)
+
+	h.Add(
+		newTemplate("gpu", api.DeviceClaimTemplateSpec{DeviceClaimSpec: &api.DeviceClaimSpec{DeviceClass: "gpu"}}),
+		newPod("my-pod", "gpu"),
+	)
+	require.Eventually(t, func() bool {
+		return getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu")) != nil
+	}, 10*time.Second, 10*time.Millisecond)
+	oldClaim := getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu"))
+
+	// A new Pod with the same name gets a new claim, not the claim of the
+	// old Pod.
+	pods := h.Clients.Dynamic.Resource(podGVR).Namespace("default")
+	require.NoError(t, pods.Delete(context.Background(), "my-pod", metav1.DeleteOptions{}))
+	h.Add(newPod("my-pod", "gpu"))
+	pod := h.Pod("default", "my-pod")
+
+	require.Eventually(t, func() bool {
+		claim := getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu"))
+		return claim != nil && claim.GetUID() != oldClaim.GetUID() && metav1.IsControlledBy(claim, pod)
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestControllerDeletedClaim(t *testing.T) {
+	h := harness.New(t)
+	startController(t, h)
+
+	h.Add(
+		newTemplate("gpu", api.DeviceClaimTemplateSpec{DeviceClaimSpec: &api.DeviceClaimSpec{DeviceClass: "gpu"}}),
+		newPod("my-pod", "gpu"),
+	)
+	require.Eventually(t, func() bool {
+		return getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu")) != nil
+	}, 10*time.Second, 10*time.Millisecond)
+	oldClaim := getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu"))
+
+	// A claim that is deleted while its Pod exists is generated again.
+	claims := h.Clients.Dynamic.Resource(claimResources["DeviceClaim"]).Namespace("default")
+	require.NoError(t, claims.Delete(context.Background(), api.PodClaimName("my-pod", "gpu"), metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool {
+		claim := getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu"))
+		return claim != nil && claim.GetUID() != oldClaim.GetUID()
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestControllerOrphanedClaimAtStartup(t *testing.T) {
+	h := harness.New(t)
+
+	// The claim of a Pod that was deleted while the controller was not
+	// running is deleted when it starts.
+	claim := &api.DeviceClaim{
+		TypeMeta: metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DeviceClaim"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      api.PodClaimName("old-pod", "gpu"),
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "Pod", Name: "old-pod", UID: "1234", Controller: ptr(true)},
+			},
+		},
+		Spec: api.DeviceClaimSpec{DeviceClass: "gpu"},
+	}
+	h.Add(claim)
+	require.NotNil(t, getClaim(t, h, "DeviceClaim", api.PodClaimName("old-pod", "gpu")))
+
+	startController(t, h)
+	require.Eventually(t, func() bool {
+		return getClaim(t, h, "DeviceClaim", api.PodClaimName("old-pod", "gpu")) == nil
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestControllerInvalidTemplate(t *testing.T) {
+	h := harness.New(t)
+	startController(t, h)
+
+	// The constraints do not compile, so no claim is generated from the
+	// template, but the other claims of the Pod are.
+	h.Add(
+		newTemplate("broken", api.DeviceClaimTemplateSpec{
+			DeviceClaimSpec: &api.DeviceClaimSpec{DeviceClass: "gpu", Constraints: ptr("device.vendor ==")},
+		}),
+		newTemplate("gpu", api.DeviceClaimTemplateSpec{DeviceClaimSpec: &api.DeviceClaimSpec{DeviceClass: "gpu"}}),
+		newPod("my-pod", "broken", "gpu"),
+	)
+	require.Eventually(t, func() bool {
+		return getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "gpu")) != nil
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Nil(t, getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "broken")))
+
+	// Once the template is fixed, the claim is generated.
+	templates := h.Clients.DevMgmt.DevmgmtprotoV1alpha1().DeviceClaimTemplates("default")
+	template, err := templates.Get(context.Background(), "broken", metav1.GetOptions{})
+	require.NoError(t, err)
+	template.Spec.DeviceClaimSpec.Constraints = ptr("device.vendor == 'example.com'")
+	_, err = templates.Update(context.Background(), template, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return getClaim(t, h, "DeviceClaim", api.PodClaimName("my-pod", "broken")) != nil
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func ptr[T any](val T) *T {
+	return &val
+}
@@ -0,0 +1,210 @@
+// Package deviceclass implements the DeviceClass status controller. It matches
+// each DeviceClass to the registered DeviceDrivers that handle its DeviceType,
+// limited to the Driver of the class if it has one, and writes them to the
+// status of the class along with a Ready condition.
+package deviceclass
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned"
+	devmgmtinformers "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/informers/externalversions/devmgmtproto/v1alpha1"
+	devmgmtlisters "github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/listers/devmgmtproto/v1alpha1"
+)
+
+// Controller keeps the status of DeviceClasses up to date with the registered
+// DeviceDrivers. Classes are queued by name.
+type Controller struct {
+	client  versioned.Interface
+	classes devmgmtlisters.DeviceClassLister
+	drivers devmgmtlisters.DeviceDriverLister
+	synced  []cache.InformerSynced
+	queue   workqueue.RateLimitingInterface
+}
+
+// NewController returns a Controller that gets DeviceClasses and
+// DeviceDrivers from the informers. The informers must be started by the
+// caller.
+func NewController(client versioned.Interface, classInformer devmgmtinformers.DeviceClassInformer, driverInformer devmgmtinformers.DeviceDriverInformer) (*Controller, error) {
+	c := &Controller{
+		client:  client,
+		classes: classInformer.Lister(),
+		drivers: driverInformer.Lister(),
+		synced:  []cache.InformerSynced{classInformer.Informer().HasSynced, driverInformer.Informer().HasSynced},
+		queue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "deviceclass"),
+	}
+
+	if _, err := classInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueClass,
+		UpdateFunc: func(_, obj any) { c.enqueueClass(obj) },
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := driverInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueAllClasses,
+		UpdateFunc: func(_, obj any) { c.enqueueAllClasses(obj) },
+		DeleteFunc: c.enqueueAllClasses,
+	}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Controller) enqueueClass(obj any) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	c.queue.Add(key)
+}
+
+// enqueueAllClasses queues all classes when a driver changes, since a driver
+// may handle any number of device types, and drivers are few.
+func (c *Controller) enqueueAllClasses(any) {
+	classes, err := c.classes.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	for _, class := range classes {
+		c.enqueueClass(class)
+	}
+}
+
+// Run runs the controller with the given number of workers until the context
+// is done.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Info("starting device class controller")
+	defer klog.Info("shutting down device class controller")
+
+	if !cache.WaitForCacheSync(ctx.Done(), c.synced...) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.syncClass(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("error syncing device class %s: %w", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+// syncClass updates the status of the class, if it still exists and its
+// status has changed.
+func (c *Controller) syncClass(ctx context.Context, name string) error {
+	class, err := c.classes.Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	drivers, err := c.drivers.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	updated := class.DeepCopy()
+	ready := metav1.Condition{
+		Type:               api.DeviceClassConditionReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: class.Generation,
+	}
+	updated.Status.Drivers, ready.Reason, ready.Message = classDrivers(&class.Spec, drivers)
+	if len(updated.Status.Drivers) > 0 {
+		ready.Status = metav1.ConditionTrue
+	}
+
+	// The transition time is only changed along with the status of the
+	// condition.
+	meta.SetStatusCondition(&updated.Status.Conditions, ready)
+	if apiequality.Semantic.DeepEqual(class.Status, updated.Status) {
+		return nil
+	}
+
+	if _, err := c.client.DevmgmtprotoV1alpha1().DeviceClasses().UpdateStatus(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating status: %w", err)
+	}
+	klog.Infof("updated status of device class %s: %s", name, ready.Message)
+
+	return nil
+}
+
+// classDrivers returns the names of the drivers that can handle the class, in
+// order, along with the reason and message of the Ready condition of the
+// class.
+func classDrivers(spec *api.DeviceClassSpec, drivers []*api.DeviceDriver) ([]string, string, string) {
+	if spec.Driver != nil {
+		for _, driver := range drivers {
+			if driver.Name != *spec.Driver {
+				continue
+			}
+			if !slices.Contains(driver.DeviceTypes, spec.DeviceType) {
+				return nil, api.DeviceClassReasonDriverMismatch, fmt.Sprintf("driver %s does not handle device type %s", driver.Name, spec.DeviceType)
+			}
+			return []string{driver.Name}, api.DeviceClassReasonDriverRegistered, fmt.Sprintf("handled by driver %s", driver.Name)
+		}
+
+		return nil, api.DeviceClassReasonNoDriverRegistered, fmt.Sprintf("driver %s is not registered", *spec.Driver)
+	}
+
+	var names []string
+	for _, driver := range drivers {
+		if slices.Contains(driver.DeviceTypes, spec.DeviceType) {
+			names = append(names, driver.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, api.DeviceClassReasonNoDriverRegistered, fmt.Sprintf("no driver is registered for device type %s", spec.DeviceType)
+	}
+	sort.Strings(names)
+
+	return names, api.DeviceClassReasonDriverRegistered, fmt.Sprintf("handled by drivers %s", strings.Join(names, ", "))
+}
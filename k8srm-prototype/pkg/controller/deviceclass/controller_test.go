@@ -0,0 +1,151 @@
+package deviceclass
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/informers/externalversions"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/harness"
+)
+
+// startController runs the controller against the harness until the test
+// ends.
+func startController(t *testing.T, h *harness.Harness) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	factory := externalversions.NewSharedInformerFactory(h.Clients.DevMgmt, 0)
+
+	c, err := NewController(h.Clients.DevMgmt, factory.Devmgmtproto().V1alpha1().DeviceClasses(), factory.Devmgmtproto().V1alpha1().DeviceDrivers())
+	require.NoError(t, err)
+
+	factory.Start(ctx.Done())
+	go c.Run(ctx, 1)
+}
+
+func newDriver(name string, deviceTypes ...string) *api.DeviceDriver {
+	return &api.DeviceDriver{
+		TypeMeta:    metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DeviceDriver"},
+		ObjectMeta:  metav1.ObjectMeta{Name: name},
+		DeviceTypes: deviceTypes,
+	}
+}
+
+func newClass(name, deviceType string, driver *string) *api.DeviceClass {
+	return &api.DeviceClass{
+		TypeMeta:   metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DeviceClass"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       api.DeviceClassSpec{DeviceType: deviceType, Driver: driver},
+	}
+}
+
+// waitForReason waits until the Ready condition of the class has the reason,
+// and returns the class.
+func waitForReason(t *testing.T, h *harness.Harness, name, reason string) *api.DeviceClass {
+	var class *api.DeviceClass
+	require.Eventually(t, func() bool {
+		var err error
+		class, err = h.Clients.DevMgmt.DevmgmtprotoV1alpha1().DeviceClasses().Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		ready := meta.FindStatusCondition(class.Status.Conditions, api.DeviceClassConditionReady)
+		return ready != nil && ready.Reason == reason
+	}, 10*time.Second, 10*time.Millisecond, "device class %s did not become %s", name, reason)
+
+	return class
+}
+
+func TestClassDrivers(t *testing.T) {
+	drivers := []*api.DeviceDriver{
+		newDriver("example.com-foozer", "gpu"),
+		newDriver("example.com-barzer", "gpu", "sriov-nic"),
+		newDriver("sriov-nic", "sriov-nic"),
+	}
+
+	testCases := map[string]struct {
+		spec           api.DeviceClassSpec
+		expectedNames  []string
+		expectedReason string
+		expectedMsg    string
+	}{
+		"by device type": {
+			spec:           api.DeviceClassSpec{DeviceType: "gpu"},
+			expectedNames:  []string{"example.com-barzer", "example.com-foozer"},
+			expectedReason: api.DeviceClassReasonDriverRegistered,
+			expectedMsg:    "handled by drivers example.com-barzer, example.com-foozer",
+		},
+		"by driver": {
+			spec:           api.DeviceClassSpec{DeviceType: "sriov-nic", Driver: ptr("example.com-barzer")},
+			expectedNames:  []string{"example.com-barzer"},
+			expectedReason: api.DeviceClassReasonDriverRegistered,
+			expectedMsg:    "handled by driver example.com-barzer",
+		},
+		"unknown device type": {
+			spec:           api.DeviceClassSpec{DeviceType: "vlan"},
+			expectedReason: api.DeviceClassReasonNoDriverRegistered,
+			expectedMsg:    "no driver is registered for device type vlan",
+		},
+		"unknown driver": {
+			spec:           api.DeviceClassSpec{DeviceType: "gpu", Driver: ptr("vlan")},
+			expectedReason: api.DeviceClassReasonNoDriverRegistered,
+			expectedMsg:    "driver vlan is not registered",
+		},
+		"driver mismatch": {
+			spec:           api.DeviceClassSpec{DeviceType: "gpu", Driver: ptr("sriov-nic")},
+			expectedReason: api.DeviceClassReasonDriverMismatch,
+			expectedMsg:    "driver sriov-nic does not handle device type gpu",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			names, reason, msg := classDrivers(&tc.spec, drivers)
+			require.Equal(t, tc.expectedNames, names)
+			require.Equal(t, tc.expectedReason, reason)
+			require.Equal(t, tc.expectedMsg, msg)
+		})
+	}
+}
+
+func TestController(t *testing.T) {
+	h := harness.New(t, "../../../testdata/drivers.yaml", "../../../testdata/classes.yaml")
+	startController(t, h)
+
+	class := waitForReason(t, h, "example.com-gpu-set", api.DeviceClassReasonDriverRegistered)
+	require.Equal(t, []string{"example.com-barzer", "example.com-foozer"}, class.Status.Drivers)
+	ready := meta.FindStatusCondition(class.Status.Conditions, api.DeviceClassConditionReady)
+	require.Equal(t, metav1.ConditionTrue, ready.Status)
+
+	class = waitForReason(t, h, "example.com-foozer-single", api.DeviceClassReasonDriverRegistered)
+	require.Equal(t, []string{"example.com-foozer"}, class.Status.Drivers)
+
+	// Classes that are added later get a status too.
+	h.Add(newClass("vlan-gpu", "gpu", ptr("vlan")))
+	class = waitForReason(t, h, "vlan-gpu", api.DeviceClassReasonDriverMismatch)
+	require.Empty(t, class.Status.Drivers)
+
+	// The classes of a driver that is deleted are no longer ready.
+	require.NoError(t, h.Clients.DevMgmt.DevmgmtprotoV1alpha1().DeviceDrivers().Delete(context.Background(), "vlan", metav1.DeleteOptions{}))
+	class = waitForReason(t, h, "vlan-2000", api.DeviceClassReasonNoDriverRegistered)
+	require.Empty(t, class.Status.Drivers)
+	ready = meta.FindStatusCondition(class.Status.Conditions, api.DeviceClassConditionReady)
+	require.Equal(t, metav1.ConditionFalse, ready.Status)
+	require.Equal(t, "no driver is registered for device type vlan", ready.Message)
+
+	// A driver that is registered later makes them ready again.
+	h.Add(newDriver("vlan", "vlan", "gpu"))
+	waitForReason(t, h, "vlan-2000", api.DeviceClassReasonDriverRegistered)
+	class = waitForReason(t, h, "vlan-gpu", api.DeviceClassReasonDriverRegistered)
+	require.Equal(t, []string{"vlan"}, class.Status.Drivers)
+}
+
+func ptr[T any](val T) *T {
+	return &val
+}
@@ -0,0 +1,126 @@
+package crdvalidation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Handler returns an http.Handler that prunes and validates the custom
+// resources created or updated through it, before passing the requests on to
+// next. Invalid objects are rejected with the same errors as a real API
+// server returns.
+//
+// Unknown fields are handled according to the fieldValidation parameter of
+// the request: "Strict" rejects them, "Ignore" drops them silently, and
+// "Warn", the default, drops them with a warning.
+//
+// Only POST and PUT requests are checked. Patches are passed on as they are,
+// since checking them would mean applying them first. For PUT requests, the
+// stored object is got from next, so that transition rules can be evaluated
+// against it.
+func (v *Validator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodPut {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			WriteStatus(w, apierrors.NewBadRequest(fmt.Sprintf("error reading body: %v", err)))
+			return
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(body); err != nil || !v.Handles(obj.GroupVersionKind()) {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if unknown := v.Prune(obj); len(unknown) > 0 {
+			var messages []string
+			for _, path := range unknown {
+				messages = append(messages, fmt.Sprintf("unknown field %q", path))
+			}
+
+			switch r.URL.Query().Get("fieldValidation") {
+			case metav1.FieldValidationStrict:
+				WriteStatus(w, apierrors.NewBadRequest("strict decoding error: "+strings.Join(messages, ", ")))
+				return
+			case metav1.FieldValidationIgnore:
+			default:
+				for _, message := range messages {
+					w.Header().Add("Warning", fmt.Sprintf("299 - %q", message))
+				}
+			}
+		}
+
+		var old *unstructured.Unstructured
+		if r.Method == http.MethodPut {
+			old = getStored(next, r)
+		}
+
+		if errs := v.ValidateUpdate(obj, old); len(errs) > 0 {
+			WriteStatus(w, apierrors.NewInvalid(obj.GroupVersionKind().GroupKind(), obj.GetName(), errs))
+			return
+		}
+
+		body, err = obj.MarshalJSON()
+		if err != nil {
+			WriteStatus(w, apierrors.NewInternalError(err))
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// getStored returns the object that a PUT request replaces, by sending a GET
+// request for it to next, or nil if it cannot be got. The status of an object
+// is updated through its status subresource, which is got with the object.
+func getStored(next http.Handler, r *http.Request) *unstructured.Unstructured {
+	get := r.Clone(r.Context())
+	get.Method = http.MethodGet
+	get.URL.Path = strings.TrimSuffix(get.URL.Path, "/status")
+	get.URL.RawPath = ""
+	get.URL.RawQuery = ""
+	get.Body = http.NoBody
+	get.ContentLength = 0
+
+	w := httptest.NewRecorder()
+	next.ServeHTTP(w, get)
+	if w.Code != http.StatusOK {
+		return nil
+	}
+
+	old := &unstructured.Unstructured{}
+	if err := old.UnmarshalJSON(w.Body.Bytes()); err != nil {
+		return nil
+	}
+
+	return old
+}
+
+// WriteStatus writes the error as a Status object, which clients turn back
+// into the same error. The mock API server uses it too, for the requests that
+// it serves itself.
+func WriteStatus(w http.ResponseWriter, err *apierrors.StatusError) {
+	status := err.Status()
+	status.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Status"}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(status.Code))
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,145 @@
+package crdvalidation
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/kubebuilder-declarative-pattern/mockkubeapiserver"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/config/crd"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned"
+)
+
+// newServer starts a mock API server with the CRDs of the API, and any
+// others, loaded, behind the validating handler.
+func newServer(t *testing.T, others ...*apiextensionsv1.CustomResourceDefinition) *rest.Config {
+	k8s, err := mockkubeapiserver.NewMockKubeAPIServer("")
+	require.NoError(t, err)
+
+	crds, err := crd.CustomResourceDefinitions()
+	require.NoError(t, err)
+	crds = append(crds, others...)
+
+	for _, crd := range crds {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+		require.NoError(t, err)
+		require.NoError(t, k8s.AddObject(&unstructured.Unstructured{Object: u}))
+	}
+
+	v, err := NewValidator(crds...)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(v.Handler(k8s))
+	t.Cleanup(server.Close)
+
+	return &rest.Config{Host: server.URL}
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	config := newServer(t)
+
+	client, err := versioned.NewForConfig(config)
+	require.NoError(t, err)
+	claims := client.DevmgmtprotoV1alpha1().DeviceClaims("default")
+
+	// A valid claim is stored.
+	claim := &api.DeviceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "valid", Namespace: "default"},
+		Spec: api.DeviceClaimSpec{
+			DeviceClass:    "gpu",
+			MinDeviceCount: ptr(1),
+			MaxDeviceCount: ptr(2),
+		},
+	}
+	_, err = claims.Create(ctx, claim, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// An invalid claim is rejected, and is not stored.
+	claim = &api.DeviceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "default"},
+		Spec: api.DeviceClaimSpec{
+			DeviceClass:    "gpu",
+			MinDeviceCount: ptr(2),
+			MaxDeviceCount: ptr(1),
+		},
+	}
+	_, err = claims.Create(ctx, claim, metav1.CreateOptions{})
+	require.True(t, apierrors.IsInvalid(err), "expected an invalid error, got %v", err)
+	require.EqualError(t, err, `DeviceClaim.devmgmtproto.k8s.io "invalid" is invalid: spec: Invalid value: "object": minDeviceCount must not be greater than maxDeviceCount`)
+
+	list, err := claims.List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "valid", list.Items[0].Name)
+
+	// Unknown fields are dropped, unless strict field validation is
+	// requested.
+	dynamicClient, err := dynamic.NewForConfig(config)
+	require.NoError(t, err)
+	classes := dynamicClient.Resource(schema.GroupVersionResource{Group: api.GroupName, Version: "v1alpha1", Resource: "deviceclasses"})
+
+	class := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": api.DevMgmtAPIVersion,
+		"kind":       "DeviceClass",
+		"metadata":   map[string]any{"name": "gpu"},
+		"spec": map[string]any{
+			"deviceType":     "gpu",
+			"deviceMaxCount": int64(1),
+		},
+	}}
+	_, err = classes.Create(ctx, class, metav1.CreateOptions{FieldValidation: metav1.FieldValidationStrict})
+	require.True(t, apierrors.IsBadRequest(err), "expected a bad request error, got %v", err)
+	require.EqualError(t, err, `strict decoding error: unknown field "spec.deviceMaxCount"`)
+
+	created, err := classes.Create(ctx, class, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"deviceType": "gpu"}, created.Object["spec"])
+}
+
+func TestHandlerTransitionRule(t *testing.T) {
+	ctx := context.Background()
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(widgetCRD), crd))
+	config := newServer(t, crd)
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	require.NoError(t, err)
+	widgets := dynamicClient.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}).Namespace("default")
+
+	widget := decode(t, `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: default
+spec:
+  color: red
+`)
+	created, err := widgets.Create(ctx, widget, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// The stored object is used as the old object of an update.
+	require.NoError(t, unstructured.SetNestedField(created.Object, "blue", "spec", "color"))
+	_, err = widgets.Update(ctx, created, metav1.UpdateOptions{})
+	require.True(t, apierrors.IsInvalid(err), "expected an invalid error, got %v", err)
+	require.EqualError(t, err, `Widget.example.com "widget" is invalid: spec.color: Invalid value: "string": color is immutable`)
+}
+
+func ptr[T any](val T) *T {
+	var v T = val
+	return &v
+}
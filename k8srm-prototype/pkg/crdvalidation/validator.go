@@ -0,0 +1,488 @@
+// Package crdvalidation validates custom resources against the
+// CustomResourceDefinitions of their kinds, much like a real API server does.
+// It uses the structural schemas, pruning, and list type validation of the
+// apiextensions API server, and the OpenAPI schema validator it is built on,
+// which checks types, required fields, enums, patterns, formats, and bounds.
+// The CEL rules in x-kubernetes-validations are evaluated with cel-go, with
+// the cost limits of the API server, and transition rules (which use oldSelf)
+// are evaluated on updates.
+//
+// This exists because the mock API server stores custom resources without
+// looking at their schemas. It does not implement everything a real API
+// server does: defaults are not applied, CEL rules are compiled with self and
+// oldSelf as dynamic values rather than typed from the schema, and the
+// Kubernetes CEL libraries, messageExpression, and fieldPath are not
+// supported.
+package crdvalidation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/interpreter"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/listtype"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/objectmeta"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	openapierrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+const (
+	// perCallLimit is the most a single CEL rule may cost to evaluate, and
+	// runtimeCostBudget is the most all the rules of an object may cost,
+	// as in k8s.io/apiserver/pkg/apis/cel.
+	perCallLimit      = 1000000
+	runtimeCostBudget = 10000000
+)
+
+// Validator validates custom resources of the kinds defined by a set of CRDs.
+// CRDs can be added while it is in use.
+type Validator struct {
+	env *cel.Env
+
+	mutex sync.RWMutex
+	kinds map[schema.GroupVersionKind]kindSchema
+	rules map[string]rule
+}
+
+// kindSchema is the schema of one version of a CRD.
+type kindSchema struct {
+	namespaced bool
+	structural *structuralschema.Structural
+	validator  *validate.SchemaValidator
+}
+
+// rule is a compiled CEL rule. Transition rules refer to oldSelf, and are
+// only evaluated when there is an old value.
+type rule struct {
+	program    cel.Program
+	transition bool
+}
+
+// NewValidator returns a Validator for every served version of the CRDs. It
+// fails if any of their schemas are not structural, or any of the CEL rules
+// in them do not compile.
+func NewValidator(crds ...*apiextensionsv1.CustomResourceDefinition) (*Validator, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("self", cel.DynType),
+		cel.Variable("oldSelf", cel.DynType),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Validator{
+		env:   env,
+		kinds: make(map[schema.GroupVersionKind]kindSchema),
+		rules: make(map[string]rule),
+	}
+
+	for _, crd := range crds {
+		if err := v.AddCRD(crd); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// AddCRD adds or replaces the served versions of the CRD. It fails if any of
+// their schemas are not structural, or any of the CEL rules in them do not
+// compile, in which case none of the versions are added.
+func (v *Validator) AddCRD(crd *apiextensionsv1.CustomResourceDefinition) error {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	kinds := make(map[schema.GroupVersionKind]kindSchema)
+	for _, version := range crd.Spec.Versions {
+		if !version.Served || version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+		s, err := newStructural(version.Schema.OpenAPIV3Schema)
+		if err != nil {
+			return fmt.Errorf("%s: %w", gvk, err)
+		}
+
+		if err := v.compile(s); err != nil {
+			return fmt.Errorf("%s: %w", gvk, err)
+		}
+
+		kinds[gvk] = kindSchema{
+			namespaced: crd.Spec.Scope == apiextensionsv1.NamespaceScoped,
+			structural: s,
+			validator:  validate.NewSchemaValidator(s.ToKubeOpenAPI(), nil, "", strfmt.Default),
+		}
+	}
+
+	for gvk, ks := range kinds {
+		v.kinds[gvk] = ks
+	}
+
+	return nil
+}
+
+// newStructural converts the schema to a structural schema, and checks that
+// it is one, as the API server does when a CRD is created.
+func newStructural(in *apiextensionsv1.JSONSchemaProps) (*structuralschema.Structural, error) {
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(in, internal, nil); err != nil {
+		return nil, fmt.Errorf("error converting schema: %w", err)
+	}
+
+	s, err := structuralschema.NewStructural(internal)
+	if err != nil {
+		return nil, fmt.Errorf("error converting schema: %w", err)
+	}
+
+	if errs := structuralschema.ValidateStructural(field.NewPath("openAPIV3Schema"), s); len(errs) > 0 {
+		return nil, fmt.Errorf("schema is not structural: %w", errs.ToAggregate())
+	}
+
+	return s, nil
+}
+
+// compile compiles the CEL rules in the schema and all of its children.
+func (v *Validator) compile(s *structuralschema.Structural) error {
+	if s == nil {
+		return nil
+	}
+
+	for _, r := range s.XValidations {
+		if _, ok := v.rules[r.Rule]; ok {
+			continue
+		}
+
+		ast, iss := v.env.Compile(r.Rule)
+		if iss.Err() != nil {
+			return fmt.Errorf("error compiling rule %q: %w", r.Rule, iss.Err())
+		}
+
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return fmt.Errorf("rule %q must evaluate to a bool, not %s", r.Rule, ast.OutputType())
+		}
+
+		prg, err := v.env.Program(ast, cel.CostLimit(perCallLimit), cel.EvalOptions(cel.OptTrackCost))
+		if err != nil {
+			return fmt.Errorf("error creating program for rule %q: %w", r.Rule, err)
+		}
+
+		transition := false
+		for _, ref := range ast.NativeRep().ReferenceMap() {
+			if ref.Name == "oldSelf" {
+				transition = true
+			}
+		}
+
+		v.rules[r.Rule] = rule{program: prg, transition: transition}
+	}
+
+	for name := range s.Properties {
+		prop := s.Properties[name]
+		if err := v.compile(&prop); err != nil {
+			return err
+		}
+	}
+
+	if s.AdditionalProperties != nil {
+		if err := v.compile(s.AdditionalProperties.Structural); err != nil {
+			return err
+		}
+	}
+
+	return v.compile(s.Items)
+}
+
+// Handles returns true if the Validator has a schema for the kind.
+func (v *Validator) Handles(gvk schema.GroupVersionKind) bool {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+
+	_, ok := v.kinds[gvk]
+	return ok
+}
+
+// Prune removes the fields of the object that are not in the schema of its
+// kind, along with null values of fields that are not nullable, and returns
+// the paths of the unknown fields. The metadata of the object is left alone.
+func (v *Validator) Prune(obj *unstructured.Unstructured) []string {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+
+	ks, ok := v.kinds[obj.GroupVersionKind()]
+	if !ok {
+		return nil
+	}
+
+	pruneNulls(obj.Object, ks.structural)
+
+	return pruning.PruneWithOptions(obj.Object, ks.structural, true, structuralschema.UnknownFieldPathOptions{
+		TrackUnknownFieldPaths: true,
+	})
+}
+
+// pruneNulls removes the null values of fields that are not nullable. It does
+// what PruneNonNullableNullsWithoutDefaults in the defaulting package of the
+// apiextensions API server does, as that package cannot be used without the
+// rest of the API server.
+func pruneNulls(x any, s *structuralschema.Structural) {
+	if s == nil {
+		return
+	}
+
+	switch x := x.(type) {
+	case map[string]any:
+		for k, value := range x {
+			var propSchema *structuralschema.Structural
+			if prop, ok := s.Properties[k]; ok {
+				propSchema = &prop
+			} else if s.AdditionalProperties != nil {
+				propSchema = s.AdditionalProperties.Structural
+			}
+
+			if value == nil && propSchema != nil && !propSchema.Nullable && propSchema.Default.Object == nil {
+				delete(x, k)
+				continue
+			}
+			pruneNulls(value, propSchema)
+		}
+
+	case []any:
+		for _, item := range x {
+			pruneNulls(item, s.Items)
+		}
+	}
+}
+
+// Validate checks a new object against the schema of its kind, and the
+// metadata of the object like the API server does for every object. Objects
+// of unknown kinds are not checked. Validate should be called after Prune, so
+// that unknown fields and null values are not reported as errors.
+func (v *Validator) Validate(obj *unstructured.Unstructured) field.ErrorList {
+	return v.ValidateUpdate(obj, nil)
+}
+
+// ValidateUpdate checks an object like Validate does, and also evaluates the
+// transition rules of the schema against the old object, if there is one.
+func (v *Validator) ValidateUpdate(obj, old *unstructured.Unstructured) field.ErrorList {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+
+	ks, ok := v.kinds[obj.GroupVersionKind()]
+	if !ok {
+		return nil
+	}
+
+	allErrs := apimachineryvalidation.ValidateObjectMetaAccessor(obj, ks.namespaced,
+		apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
+	allErrs = append(allErrs, resultToFieldErrors(ks.validator.Validate(obj.Object))...)
+	allErrs = append(allErrs, objectmeta.Validate(nil, obj.Object, ks.structural, false)...)
+	allErrs = append(allErrs, listtype.ValidateListSetsAndMaps(nil, ks.structural, obj.Object)...)
+
+	// As in the API server, the CEL rules are only evaluated if the object
+	// matches its schema, as they may depend on its types.
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	var oldObject any
+	if old != nil {
+		oldObject = old.Object
+	}
+
+	budget := int64(runtimeCostBudget)
+	return v.evaluate(obj.Object, oldObject, ks.structural, nil, &budget)
+}
+
+// resultToFieldErrors converts the errors of the OpenAPI schema validator to
+// field errors, the same way the API server does.
+func resultToFieldErrors(result *validate.Result) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, err := range result.Errors {
+		var verr *openapierrors.Validation
+		if !errors.As(err, &verr) {
+			allErrs = append(allErrs, field.Invalid(nil, "", err.Error()))
+			continue
+		}
+
+		var errPath *field.Path
+		if len(verr.Name) > 0 && verr.Name != "." {
+			errPath = errPath.Child(strings.TrimPrefix(verr.Name, "."))
+		}
+
+		value := verr.Value
+		if value == nil {
+			value = ""
+		}
+
+		switch verr.Code() {
+		case openapierrors.RequiredFailCode:
+			allErrs = append(allErrs, field.Required(errPath, ""))
+
+		case openapierrors.EnumFailCode:
+			var allowed []string
+			for _, a := range verr.Values {
+				if s, ok := a.(string); ok {
+					allowed = append(allowed, s)
+				} else {
+					data, _ := json.Marshal(a)
+					allowed = append(allowed, string(data))
+				}
+			}
+			allErrs = append(allErrs, field.NotSupported(errPath, verr.Value, allowed))
+
+		case openapierrors.TooLongFailCode:
+			allErrs = append(allErrs, field.TooLongMaxLength(errPath, value, int(toInt(verr.Valid))))
+
+		case openapierrors.MaxItemsFailCode, openapierrors.TooManyPropertiesCode:
+			allErrs = append(allErrs, field.TooMany(errPath, int(toInt(verr.Value)), int(toInt(verr.Valid))))
+
+		case openapierrors.InvalidTypeCode:
+			allErrs = append(allErrs, field.TypeInvalid(errPath, value, verr.Error()))
+
+		default:
+			allErrs = append(allErrs, field.Invalid(errPath, value, verr.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func toInt(value any) int64 {
+	if i, ok := value.(int64); ok {
+		return i
+	}
+
+	return -1
+}
+
+// evaluate evaluates the CEL rules of the schema and its children with the
+// value as self, and the old value, if there is one, as oldSelf. The cost of
+// the rules is taken from the budget, and no more rules are evaluated once it
+// is used up.
+func (v *Validator) evaluate(value, old any, s *structuralschema.Structural, fldPath *field.Path, budget *int64) field.ErrorList {
+	if value == nil || s == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	for _, r := range s.XValidations {
+		compiled := v.rules[r.Rule]
+		if compiled.transition && old == nil {
+			continue
+		}
+
+		out, details, err := compiled.program.Eval(map[string]any{"self": value, "oldSelf": old})
+		if details != nil && details.ActualCost() != nil {
+			cost := *details.ActualCost()
+			if cost > math.MaxInt64 || int64(cost) > *budget {
+				*budget = -1
+				return append(allErrs, field.Invalid(fldPath, s.Type, "validation failed due to running out of cost budget, no further validation rules will be run"))
+			}
+			*budget -= int64(cost)
+		}
+
+		var cancelled interpreter.EvalCancelledError
+		switch {
+		case errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded:
+			*budget = -1
+			return append(allErrs, field.Invalid(fldPath, s.Type, fmt.Sprintf("no further validation rules will be run due to call cost exceeds limit for rule: %s", r.Rule)))
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath, s.Type, fmt.Sprintf("%v evaluating rule: %s", err, r.Rule)))
+		case out != types.True:
+			message := strings.TrimSpace(r.Message)
+			if message == "" {
+				message = fmt.Sprintf("failed rule: %s", r.Rule)
+			}
+			allErrs = append(allErrs, field.Invalid(fldPath, s.Type, message))
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		oldMap, _ := old.(map[string]any)
+		for _, name := range sortedKeys(value) {
+			if prop, ok := s.Properties[name]; ok {
+				allErrs = append(allErrs, v.evaluate(value[name], oldMap[name], &prop, fldPath.Child(name), budget)...)
+			} else if s.AdditionalProperties != nil {
+				allErrs = append(allErrs, v.evaluate(value[name], oldMap[name], s.AdditionalProperties.Structural, fldPath.Key(name), budget)...)
+			}
+			if *budget < 0 {
+				return allErrs
+			}
+		}
+
+	case []any:
+		oldList, _ := old.([]any)
+		for i, item := range value {
+			allErrs = append(allErrs, v.evaluate(item, oldItem(item, oldList, s), s.Items, fldPath.Index(i), budget)...)
+			if *budget < 0 {
+				return allErrs
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// oldItem returns the item of the old list that has the same keys as the item,
+// if the list is a map. Items of other lists cannot be matched up with the
+// old ones, so their transition rules are not evaluated.
+func oldItem(item any, oldList []any, s *structuralschema.Structural) any {
+	if s.XListType == nil || *s.XListType != "map" {
+		return nil
+	}
+
+	itemMap, ok := item.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	for _, old := range oldList {
+		oldMap, ok := old.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		matches := true
+		for _, key := range s.XListMapKeys {
+			// The keys are scalars, which can be compared.
+			if itemMap[key] != oldMap[key] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return old
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
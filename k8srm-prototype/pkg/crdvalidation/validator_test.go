@@ -0,0 +1,459 @@
+package crdvalidation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/config/crd"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/gen"
+)
+
+func newValidator(t *testing.T) *Validator {
+	crds, err := crd.CustomResourceDefinitions()
+	require.NoError(t, err)
+
+	v, err := NewValidator(crds...)
+	require.NoError(t, err)
+
+	return v
+}
+
+// decode decodes the object from JSON, as it is when it is sent to the API
+// server.
+func decode(t *testing.T, object string) *unstructured.Unstructured {
+	data, err := yaml.YAMLToJSON([]byte(object))
+	require.NoError(t, err)
+
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, obj.UnmarshalJSON(data))
+
+	return obj
+}
+
+func TestValidator(t *testing.T) {
+	testCases := map[string]struct {
+		object      string
+		expectPrune []string
+		expectErrs  []string
+	}{
+		"valid claim": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClaim
+metadata:
+  name: claim
+  namespace: default
+spec:
+  deviceClass: gpu
+  minDeviceCount: 2
+  maxDeviceCount: 2
+`,
+		},
+		"claim min greater than max": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClaim
+metadata:
+  name: claim
+  namespace: default
+spec:
+  deviceClass: gpu
+  minDeviceCount: 3
+  maxDeviceCount: 2
+`,
+			expectErrs: []string{`spec: Invalid value: "object": minDeviceCount must not be greater than maxDeviceCount`},
+		},
+		"class min greater than max": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClass
+metadata:
+  name: gpu
+spec:
+  deviceType: gpu
+  minDeviceCount: 3
+  maxDeviceCount: 2
+`,
+			expectErrs: []string{`spec: Invalid value: "object": minDeviceCount must not be greater than maxDeviceCount`},
+		},
+		"missing required field": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClaim
+metadata:
+  name: claim
+  namespace: default
+spec:
+  maxDeviceCount: 2
+`,
+			expectErrs: []string{`spec.deviceClass: Required value`},
+		},
+		"wrong type": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClaim
+metadata:
+  name: claim
+  namespace: default
+spec:
+  deviceClass: gpu
+  maxDeviceCount: two
+`,
+			expectErrs: []string{`spec.maxDeviceCount: Invalid value: "string": spec.maxDeviceCount in body must be of type integer: "string"`},
+		},
+		"template with no spec": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClaimTemplate
+metadata:
+  name: template
+  namespace: default
+spec:
+  metadata:
+    labels:
+      app: test
+`,
+			expectErrs: []string{`spec: Invalid value: "object": exactly one of claimSpec, privilegedClaimSpec, or setClaimSpec must be set`},
+		},
+		"template with two specs": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClaimTemplate
+metadata:
+  name: template
+  namespace: default
+spec:
+  claimSpec:
+    deviceClass: gpu
+  privilegedClaimSpec:
+    driver: example.com-foozer
+`,
+			expectErrs: []string{`spec: Invalid value: "object": exactly one of claimSpec, privilegedClaimSpec, or setClaimSpec must be set`},
+		},
+		"attribute with two values": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DevicePool
+metadata:
+  name: pool
+spec:
+  driver: example.com-foozer
+  count: 1
+  attributes:
+  - name: model
+    stringValue: foozer-1000
+    intValue: 1000
+  - name: memory
+    quantityValue: 40Gi
+`,
+			expectErrs: []string{`spec.attributes[0]: Invalid value: "object": exactly one of stringValue, intValue, quantityValue, or semVerValue must be set`},
+		},
+		"invalid quantity": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DevicePool
+metadata:
+  name: pool
+spec:
+  driver: example.com-foozer
+  attributes:
+  - name: memory
+    quantityValue: forty
+`,
+			expectErrs: []string{`spec.attributes[0].quantityValue: Invalid value: "forty": spec.attributes[0].quantityValue in body should match '^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$'`},
+		},
+		"unknown fields are pruned": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClass
+metadata:
+  name: gpu
+spec:
+  deviceType: gpu
+  deviceMaxCount: 1
+extra: true
+`,
+			expectPrune: []string{"extra", "spec.deviceMaxCount"},
+		},
+		"invalid name": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceClass
+metadata:
+  name: Not_A_Name
+spec:
+  deviceType: gpu
+`,
+			expectErrs: []string{`metadata.name: Invalid value: "Not_A_Name": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`},
+		},
+		"namespace on cluster scoped kind": {
+			object: `
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DeviceDriver
+metadata:
+  name: example.com-foozer
+  namespace: default
+deviceTypes:
+- gpu
+`,
+			expectErrs: []string{`metadata.namespace: Forbidden: not allowed on this type`},
+		},
+	}
+
+	v := newValidator(t)
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			obj := decode(t, tc.object)
+			require.True(t, v.Handles(obj.GroupVersionKind()))
+
+			require.Equal(t, tc.expectPrune, v.Prune(obj))
+
+			var errs []string
+			for _, err := range v.Validate(obj) {
+				errs = append(errs, err.Error())
+			}
+			require.Equal(t, tc.expectErrs, errs)
+		})
+	}
+}
+
+// TestValidatorExamples checks that the examples in testdata, and the pools
+// generated by the schedule CLI, are valid.
+func TestValidatorExamples(t *testing.T) {
+	v := newValidator(t)
+
+	files, err := filepath.Glob("../../testdata/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	var objs []any
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		decoded, err := api.DecodeStrict(data)
+		require.NoError(t, err)
+		objs = append(objs, decoded...)
+	}
+
+	for _, pool := range gen.GenFoozerBarzerNodes(2) {
+		pool.APIVersion = api.DevMgmtAPIVersion
+		pool.Kind = "DevicePool"
+		objs = append(objs, &pool)
+	}
+
+	for _, obj := range objs {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		require.NoError(t, err)
+
+		u := &unstructured.Unstructured{Object: content}
+		if !v.Handles(u.GroupVersionKind()) {
+			continue
+		}
+
+		// Converting a typed object adds a null creationTimestamp to
+		// the embedded metadata of a template, which is not in the
+		// schema, and is dropped as a real API server does.
+		for _, path := range v.Prune(u) {
+			require.Equal(t, "spec.metadata.creationTimestamp", path, "%s %s", u.GetKind(), u.GetName())
+		}
+		require.Empty(t, v.Validate(u), "%s %s", u.GetKind(), u.GetName())
+	}
+}
+
+// widgetCRD has a transition rule, and list types that are checked.
+const widgetCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          apiVersion:
+            type: string
+          kind:
+            type: string
+          metadata:
+            type: object
+          spec:
+            type: object
+            properties:
+              color:
+                type: string
+                x-kubernetes-validations:
+                - rule: self == oldSelf
+                  message: color is immutable
+              tags:
+                type: array
+                items:
+                  type: string
+                x-kubernetes-list-type: set
+              parts:
+                type: array
+                items:
+                  type: object
+                  required: ["name"]
+                  properties:
+                    name:
+                      type: string
+                    size:
+                      type: integer
+                      x-kubernetes-validations:
+                      - rule: self >= oldSelf
+                        message: size cannot shrink
+                x-kubernetes-list-type: map
+                x-kubernetes-list-map-keys: ["name"]
+              numbers:
+                type: array
+                items:
+                  type: integer
+                x-kubernetes-validations:
+                - rule: self.all(x, self.all(y, self.all(z, x + y + z >= 0)))
+`
+
+func TestValidatorUpdate(t *testing.T) {
+	testCases := map[string]struct {
+		object     string
+		old        string
+		expectErrs []string
+	}{
+		"create": {
+			object: `
+spec:
+  color: red
+  parts:
+  - name: wheel
+    size: 1
+`,
+		},
+		"unchanged": {
+			object: `
+spec:
+  color: red
+  parts:
+  - name: wheel
+    size: 1
+`,
+			old: `
+spec:
+  color: red
+  parts:
+  - name: wheel
+    size: 1
+`,
+		},
+		"changed immutable field": {
+			object: `
+spec:
+  color: blue
+`,
+			old: `
+spec:
+  color: red
+`,
+			expectErrs: []string{`spec.color: Invalid value: "string": color is immutable`},
+		},
+		"map list items are matched by key": {
+			object: `
+spec:
+  parts:
+  - name: door
+    size: 1
+  - name: wheel
+    size: 1
+`,
+			old: `
+spec:
+  parts:
+  - name: wheel
+    size: 2
+  - name: door
+    size: 1
+`,
+			expectErrs: []string{`spec.parts[1].size: Invalid value: "integer": size cannot shrink`},
+		},
+		"duplicate set items": {
+			object: `
+spec:
+  tags: [a, b, a]
+`,
+			expectErrs: []string{`spec.tags[2]: Duplicate value: "a"`},
+		},
+		"duplicate map keys": {
+			object: `
+spec:
+  parts:
+  - name: wheel
+  - name: wheel
+`,
+			expectErrs: []string{`spec.parts[1]: Duplicate value: map[string]interface {}{"name":"wheel"}`},
+		},
+		"rule over cost limit": {
+			object: fmt.Sprintf(`
+spec:
+  numbers: [%s]
+`, strings.Repeat("1, ", 200)+"1"),
+			expectErrs: []string{`spec.numbers: Invalid value: "array": no further validation rules will be run due to call cost exceeds limit for rule: self.all(x, self.all(y, self.all(z, x + y + z >= 0)))`},
+		},
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(widgetCRD), crd))
+	v, err := NewValidator(crd)
+	require.NoError(t, err)
+
+	header := `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: default
+`
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			obj := decode(t, header+tc.object)
+			var old *unstructured.Unstructured
+			if tc.old != "" {
+				old = decode(t, header+tc.old)
+			}
+
+			var errs []string
+			for _, err := range v.ValidateUpdate(obj, old) {
+				errs = append(errs, err.Error())
+			}
+			require.Equal(t, tc.expectErrs, errs)
+		})
+	}
+}
+
+func TestValidatorNotStructural(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(widgetCRD), crd))
+	crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"] = apiextensionsv1.JSONSchemaProps{}
+
+	_, err := NewValidator(crd)
+	require.ErrorContains(t, err, "schema is not structural")
+}
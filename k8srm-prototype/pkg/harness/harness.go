@@ -0,0 +1,166 @@
+// Package harness runs the mock API server in-process, so that tests can load
+// manifests into it, run the scheduling flow against it, and check the
+// objects that were written, without an external cluster.
+//
+//	h := harness.New(t, "../../testdata/drivers.yaml", "../../testdata/classes.yaml")
+//	h.Add(pools...)
+//	result, err := h.SchedulePod("default", "my-pod")
+//	claim := h.DeviceClaim("default", api.PodClaimName("my-pod", "gpu"))
+package harness
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/mockapiserver"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/podscheduler"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/schedule"
+)
+
+var podGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// Harness is a mock API server running for the duration of a test. Its
+// methods fail the test on errors, except for those of the flow under test.
+type Harness struct {
+	t      testing.TB
+	server *mockapiserver.Server
+
+	// Config is the client configuration for the server.
+	Config *rest.Config
+
+	// Clients are the clients for the server that the scheduling flow
+	// uses, which tests can use to read or change objects.
+	Clients *podscheduler.Clients
+}
+
+// New starts a mock API server on an ephemeral port of the loopback
+// interface, and loads the manifests into it. The server is stopped when the
+// test ends.
+func New(t testing.TB, manifests ...string) *Harness {
+	t.Helper()
+
+	server, err := mockapiserver.New()
+	if err != nil {
+		t.Fatalf("error creating mock API server: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+
+	// The mock API server only notices that a watch has ended when it
+	// sends the next event, so the server is closed without waiting for the
+	// requests in progress, unlike an httptest.Server.
+	httpServer := &http.Server{Handler: server}
+	go httpServer.Serve(listener)
+	t.Cleanup(func() { httpServer.Close() })
+
+	// The server is local, so there is no need to limit the rate of
+	// requests.
+	config := &rest.Config{Host: "http://" + listener.Addr().String(), QPS: -1}
+
+	clients, err := podscheduler.NewClients(config)
+	if err != nil {
+		t.Fatalf("error creating clients: %v", err)
+	}
+
+	h := &Harness{
+		t:       t,
+		server:  server,
+		Config:  config,
+		Clients: clients,
+	}
+	h.Load(manifests...)
+
+	return h
+}
+
+// Load reads the objects from the manifest files, or directories of them, and
+// adds them to the server, in order.
+func (h *Harness) Load(manifests ...string) {
+	h.t.Helper()
+
+	for _, path := range manifests {
+		objs, err := mockapiserver.ReadManifests(path)
+		if err != nil {
+			h.t.Fatalf("error reading %s: %v", path, err)
+		}
+
+		if err := h.server.AddObjects(objs); err != nil {
+			h.t.Fatalf("error loading %s: %v", path, err)
+		}
+	}
+}
+
+// Add adds the objects to the server, in order. They must have their
+// apiVersion and kind set, like the pools from the gen package.
+func (h *Harness) Add(objs ...runtime.Object) {
+	h.t.Helper()
+
+	var us []*unstructured.Unstructured
+	for _, obj := range objs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			h.t.Fatalf("error converting %T: %v", obj, err)
+		}
+		us = append(us, &unstructured.Unstructured{Object: u})
+	}
+
+	if err := h.server.AddObjects(us); err != nil {
+		h.t.Fatalf("error adding objects: %v", err)
+	}
+}
+
+// SchedulePod schedules the Pod as the schedule CLI does, with the options
+// passed on to the scheduler.
+func (h *Harness) SchedulePod(namespace, name string, opts ...schedule.Option) (*podscheduler.Result, error) {
+	return podscheduler.SchedulePod(context.Background(), h.Clients, namespace, name, opts...)
+}
+
+// Objects returns all of the objects in the server, as they would be saved to
+// a snapshot.
+func (h *Harness) Objects() []*unstructured.Unstructured {
+	h.t.Helper()
+
+	objs, err := h.server.Objects(context.Background())
+	if err != nil {
+		h.t.Fatalf("error listing objects: %v", err)
+	}
+
+	return objs
+}
+
+// Pod returns the Pod. Pods are unstructured, since they have fields that are
+// not part of the real PodSpec.
+func (h *Harness) Pod(namespace, name string) *unstructured.Unstructured {
+	h.t.Helper()
+
+	pod, err := h.Clients.Dynamic.Resource(podGVR).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		h.t.Fatalf("error getting pod %s/%s: %v", namespace, name, err)
+	}
+
+	return pod
+}
+
+// DeviceClaim returns the DeviceClaim.
+func (h *Harness) DeviceClaim(namespace, name string) *api.DeviceClaim {
+	h.t.Helper()
+
+	claim, err := h.Clients.DevMgmt.DevmgmtprotoV1alpha1().DeviceClaims(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		h.t.Fatalf("error getting device claim %s/%s: %v", namespace, name, err)
+	}
+
+	return claim
+}
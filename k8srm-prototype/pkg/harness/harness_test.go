@@ -0,0 +1,350 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/informers/externalversions"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/controller/claimtemplate"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/gen"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/podscheduler"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/schedule"
+)
+
+// startClaimTemplateController runs the claim template controller against the
+// harness until the test ends.
+func startClaimTemplateController(t *testing.T, h *Harness) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	dynamicFactory := dynamicinformer.NewDynamicSharedInformerFactory(h.Clients.Dynamic, 0)
+	factory := externalversions.NewSharedInformerFactory(h.Clients.DevMgmt, 0)
+
+	c, err := claimtemplate.NewController(h.Clients.Dynamic, dynamicFactory, factory.Devmgmtproto().V1alpha1().DeviceClaimTemplates())
+	require.NoError(t, err)
+
+	dynamicFactory.Start(ctx.Done())
+	factory.Start(ctx.Done())
+	go c.Run(ctx, 1)
+}
+
+func TestSchedulePod(t *testing.T) {
+	testCases := map[string]struct {
+		manifests  []string
+		pools      []api.DevicePool
+		controller bool
+		pod        string
+		claim      string
+		created    bool
+		err        string
+		errIs      error
+	}{
+		"embedded claim": {
+			manifests: []string{"../../testdata/pod-embedded-foozer-single.yaml"},
+			pools:     gen.GenShapeZero(1),
+			pod:       "embedded-foozer-claim",
+			claim:     api.PodClaimName("embedded-foozer-claim", "foozer-gpu"),
+			created:   true,
+		},
+		"claim reference": {
+			manifests: []string{"../../testdata/foozer-crd.yaml", "../../testdata/pod-ref-foozer-single.yaml"},
+			pools:     gen.GenShapeZero(1),
+			pod:       "template-foozer-claim",
+			claim:     "example.com-foozer-single-superfast-claim",
+		},
+		"claim template": {
+			manifests:  []string{"../../testdata/foozer-crd.yaml", "../../testdata/pod-template-foozer-single.yaml"},
+			pools:      gen.GenShapeZero(1),
+			controller: true,
+			pod:        "template-foozer-claim",
+			claim:      api.PodClaimName("template-foozer-claim", "foozer-gpu"),
+			created:    true,
+		},
+		"claim template without controller": {
+			manifests: []string{"../../testdata/foozer-crd.yaml", "../../testdata/pod-template-foozer-single.yaml"},
+			pools:     gen.GenShapeZero(1),
+			pod:       "template-foozer-claim",
+			err:       fmt.Sprintf("device claim default/%s has not been generated from template example.com-foozer-single-superfast-claim yet; is the claim template controller running?", api.PodClaimName("template-foozer-claim", "foozer-gpu")),
+		},
+		"no pools": {
+			manifests: []string{"../../testdata/pod-embedded-foozer-single.yaml"},
+			pod:       "embedded-foozer-claim",
+			err:       "no node can satisfy the device claims for pod default/embedded-foozer-claim",
+			errIs:     podscheduler.ErrUnschedulable,
+		},
+		"wrong driver": {
+			manifests: []string{"../../testdata/pod-embedded-foozer-single.yaml"},
+			pools:     gen.GenShapeThree(1),
+			pod:       "embedded-foozer-claim",
+			err:       "no node can satisfy the device claims for pod default/embedded-foozer-claim",
+			errIs:     podscheduler.ErrUnschedulable,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			h := New(t, "../../testdata/drivers.yaml", "../../testdata/classes.yaml")
+			for i := range tc.pools {
+				h.Add(&tc.pools[i])
+			}
+			h.Load(tc.manifests...)
+
+			if tc.controller {
+				startClaimTemplateController(t, h)
+				require.Eventually(t, func() bool {
+					_, err := h.Clients.DevMgmt.DevmgmtprotoV1alpha1().DeviceClaims("default").Get(context.Background(), tc.claim, metav1.GetOptions{})
+					return err == nil
+				}, 10*time.Second, 10*time.Millisecond)
+			}
+
+			result, err := h.SchedulePod("default", tc.pod)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				if tc.errIs != nil {
+					require.ErrorIs(t, err, tc.errIs)
+					require.NotNil(t, result)
+				}
+
+				// Nothing is written if the Pod cannot be scheduled.
+				nodeName, _, _ := unstructured.NestedString(h.Pod("default", tc.pod).Object, "spec", "nodeName")
+				require.Empty(t, nodeName)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "shape-zero-00", result.NodeName)
+
+			pod := h.Pod("default", tc.pod)
+			nodeName, _, _ := unstructured.NestedString(pod.Object, "spec", "nodeName")
+			require.Equal(t, "shape-zero-00", nodeName)
+
+			// The allocations are written to the status of the claim.
+			claim := h.DeviceClaim("default", tc.claim)
+			require.Equal(t, []api.DevicePoolAllocation{
+				{DevicePoolName: "shape-zero-00-foozer-00", DeviceCount: 1},
+			}, claim.Status.Allocations)
+			require.Equal(t, []string{tc.pod}, claim.Status.PodNames)
+			require.Len(t, result.Claims, 1)
+			require.Equal(t, claim.Status, result.Claims[0].Status)
+
+			if tc.created {
+				require.True(t, metav1.IsControlledBy(claim, pod))
+			} else {
+				require.Empty(t, claim.OwnerReferences)
+			}
+		})
+	}
+}
+
+// TestSchedulePodSetAndPrivilegedClaims checks that the set and privileged
+// claims generated from templates are scheduled along with the other claims of
+// the Pod, and their allocations written.
+func TestSchedulePodSetAndPrivilegedClaims(t *testing.T) {
+	h := New(t, "../../testdata/drivers.yaml", "../../testdata/classes.yaml")
+	pools := gen.GenFoozerBarzerNodes(1)
+	for i := range pools {
+		h.Add(&pools[i])
+	}
+
+	h.Add(
+		&api.DeviceClaimTemplate{
+			TypeMeta:   metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DeviceClaimTemplate"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foozer-and-barzer", Namespace: "default"},
+			Spec: api.DeviceClaimTemplateSpec{
+				DeviceSetClaimSpec: &api.DeviceSetClaimSpec{
+					MatchAttributes: []string{"numa"},
+					ClaimSpec: []api.DeviceClaimSpec{
+						{DeviceClass: "example.com-foozer-single"},
+						{DeviceClass: "example.com-barzer-gpu-single"},
+					},
+				},
+			},
+		},
+		&api.DeviceClaimTemplate{
+			TypeMeta:   metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DeviceClaimTemplate"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foozer-monitor", Namespace: "default"},
+			Spec: api.DeviceClaimTemplateSpec{
+				DevicePrivilegedClaimSpec: &api.DevicePrivilegedClaimSpec{Driver: "example.com-foozer"},
+			},
+		},
+		&unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "my-pod", "namespace": "default"},
+			"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "my-container", "image": "registry.k8s.io/pause:3.6"}},
+				"deviceClaims": []any{
+					map[string]any{"name": "gpu", "claim": map[string]any{"deviceClass": "example.com-foozer-single"}},
+					map[string]any{"name": "set", "claimTemplateName": "foozer-and-barzer"},
+					map[string]any{"name": "monitor", "claimTemplateName": "foozer-monitor"},
+				},
+			},
+		}},
+	)
+
+	startClaimTemplateController(t, h)
+	require.Eventually(t, func() bool {
+		_, err := h.Clients.DevMgmt.DevmgmtprotoV1alpha1().DevicePrivilegedClaims("default").Get(context.Background(), api.PodClaimName("my-pod", "monitor"), metav1.GetOptions{})
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+
+	result, err := h.SchedulePod("default", "my-pod")
+	require.NoError(t, err)
+	require.Equal(t, "shape-foozer-barzer-00", result.NodeName)
+	require.Len(t, result.Claims, 1)
+	require.Len(t, result.SetClaims, 1)
+	require.Len(t, result.PrivilegedClaims, 1)
+
+	// Both members of the set are allocated from the same numa node.
+	setClaim, err := h.Clients.DevMgmt.DevmgmtprotoV1alpha1().DeviceSetClaims("default").Get(context.Background(), api.PodClaimName("my-pod", "set"), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, result.SetClaims[0].Status, setClaim.Status)
+	require.Len(t, setClaim.Status.ClaimStatus, 2)
+	foozer, barzer := setClaim.Status.ClaimStatus[0], setClaim.Status.ClaimStatus[1]
+	require.Len(t, foozer.Allocations, 1)
+	require.Len(t, barzer.Allocations, 1)
+	require.Equal(t, []string{"my-pod"}, foozer.PodNames)
+	numa := strings.TrimPrefix(foozer.Allocations[0].DevicePoolName, "shape-foozer-barzer-00-foozer-")
+	require.Equal(t, "shape-foozer-barzer-00-barzer-"+numa, barzer.Allocations[0].DevicePoolName)
+
+	// The privileged claim gets all the foozers on the node, including
+	// those allocated to the other claims.
+	privilegedClaim, err := h.Clients.DevMgmt.DevmgmtprotoV1alpha1().DevicePrivilegedClaims("default").Get(context.Background(), api.PodClaimName("my-pod", "monitor"), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []api.DevicePoolAllocation{
+		{DevicePoolName: "shape-foozer-barzer-00-foozer-00", DeviceCount: 2},
+		{DevicePoolName: "shape-foozer-barzer-00-foozer-01", DeviceCount: 2},
+		{DevicePoolName: "shape-foozer-barzer-00-foozer-02", DeviceCount: 2},
+		{DevicePoolName: "shape-foozer-barzer-00-foozer-03", DeviceCount: 2},
+	}, privilegedClaim.Status.Allocations)
+	require.Equal(t, []string{"my-pod"}, privilegedClaim.Status.PodNames)
+}
+
+// TestSchedulePodRetry checks that scheduling can be retried after an earlier
+// attempt created the embedded claim but failed before binding the Pod.
+func TestSchedulePodRetry(t *testing.T) {
+	testCases := map[string]struct {
+		controlled bool
+		err        string
+	}{
+		"claim created for the pod": {
+			controlled: true,
+		},
+		"claim not created for the pod": {
+			err: fmt.Sprintf("error writing claim default/%s: the claim already exists and was not created for pod embedded-foozer-claim", api.PodClaimName("embedded-foozer-claim", "foozer-gpu")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			h := New(t, "../../testdata/drivers.yaml", "../../testdata/classes.yaml", "../../testdata/pod-embedded-foozer-single.yaml")
+
+			// The single pool has two devices, both allocated to the
+			// claim by the earlier attempt.
+			pools := gen.GenShapeZero(1)
+			h.Add(&pools[0])
+
+			pod := h.Pod("default", "embedded-foozer-claim")
+			claim := &api.DeviceClaim{
+				TypeMeta:   metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DeviceClaim"},
+				ObjectMeta: metav1.ObjectMeta{Name: api.PodClaimName("embedded-foozer-claim", "foozer-gpu"), Namespace: "default"},
+				Spec:       api.DeviceClaimSpec{DeviceClass: "example.com-foozer-single"},
+				Status: api.DeviceClaimStatus{
+					Allocations: []api.DevicePoolAllocation{{DevicePoolName: "shape-zero-00-foozer-00", DeviceCount: 2}},
+					PodNames:    []string{"embedded-foozer-claim"},
+				},
+			}
+			if tc.controlled {
+				claim.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(pod, pod.GroupVersionKind())}
+			}
+			h.Add(claim)
+
+			result, err := h.SchedulePod("default", "embedded-foozer-claim")
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "shape-zero-00", result.NodeName)
+
+			claim = h.DeviceClaim("default", api.PodClaimName("embedded-foozer-claim", "foozer-gpu"))
+			require.Equal(t, []api.DevicePoolAllocation{
+				{DevicePoolName: "shape-zero-00-foozer-00", DeviceCount: 1},
+			}, claim.Status.Allocations)
+			require.Equal(t, []string{"embedded-foozer-claim"}, claim.Status.PodNames)
+		})
+	}
+}
+
+// TestSchedulePodSharedNetworkPools checks that the Pod is bound to the node
+// with the best score, when every node gets the same allocations from a
+// network attached pool.
+func TestSchedulePodSharedNetworkPools(t *testing.T) {
+	h := New(t, "../../testdata/drivers.yaml", "../../testdata/classes.yaml", "../../testdata/pod-embedded-foozer-single.yaml")
+
+	newPool := func(name string, node *string, driver string, count int) *api.DevicePool {
+		return &api.DevicePool{
+			TypeMeta:   metav1.TypeMeta{APIVersion: api.DevMgmtAPIVersion, Kind: "DevicePool"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       api.DevicePoolSpec{NodeName: node, Driver: driver, DeviceCount: count},
+		}
+	}
+	nodeA, nodeB := "node-a", "node-b"
+	h.Add(
+		newPool("network-foozer", nil, "example.com-foozer", 2),
+		newPool("node-a-nic", &nodeA, "sriov-nic", 12),
+		newPool("node-b-nic", &nodeB, "sriov-nic", 3),
+	)
+
+	// node-a scores 7 and node-b scores 20, with the same allocations.
+	result, err := h.SchedulePod("default", "embedded-foozer-claim", schedule.WithScorer(schedule.MostAllocatedScorer{}))
+	require.NoError(t, err)
+	require.Equal(t, "node-b", result.NodeName)
+
+	nodeName, _, _ := unstructured.NestedString(h.Pod("default", "embedded-foozer-claim").Object, "spec", "nodeName")
+	require.Equal(t, "node-b", nodeName)
+	require.Equal(t, []api.DevicePoolAllocation{
+		{DevicePoolName: "network-foozer", DeviceCount: 1},
+	}, h.DeviceClaim("default", api.PodClaimName("embedded-foozer-claim", "foozer-gpu")).Status.Allocations)
+}
+
+func TestSchedulePodConsumesDevices(t *testing.T) {
+	h := New(t, "../../testdata/drivers.yaml", "../../testdata/classes.yaml", "../../testdata/pod-embedded-foozer-single.yaml")
+
+	// The single pool has two devices, and each pod claims one of them.
+	pools := gen.GenShapeZero(1)
+	h.Add(&pools[0])
+
+	pod := h.Pod("default", "embedded-foozer-claim")
+	for _, name := range []string{"first", "second", "third"} {
+		p := pod.DeepCopy()
+		p.SetName(name)
+		p.SetUID("")
+		p.SetResourceVersion("")
+		h.Add(p)
+	}
+
+	_, err := h.SchedulePod("default", "first")
+	require.NoError(t, err)
+	_, err = h.SchedulePod("default", "second")
+	require.NoError(t, err)
+	_, err = h.SchedulePod("default", "third")
+	require.ErrorIs(t, err, podscheduler.ErrUnschedulable)
+
+	var claims []string
+	for _, obj := range h.Objects() {
+		if obj.GetKind() == "DeviceClaim" {
+			claims = append(claims, obj.GetName())
+		}
+	}
+	require.Equal(t, []string{api.PodClaimName("first", "foozer-gpu"), api.PodClaimName("second", "foozer-gpu")}, claims)
+}
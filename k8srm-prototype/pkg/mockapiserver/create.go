@@ -0,0 +1,81 @@
+package mockapiserver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/crdvalidation"
+)
+
+// createHandler rejects the creation of an object that already exists with an
+// AlreadyExists error, which the mock API server reports as an internal error,
+// so that clients can tell the two apart. Other requests are passed on to
+// next.
+func (s *Server) createHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gr, namespace, ok := collectionPath(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			crdvalidation.WriteStatus(w, apierrors.NewBadRequest(fmt.Sprintf("error reading body: %v", err)))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Anything the server cannot handle is left for it to report.
+		obj := &unstructured.Unstructured{}
+		resource := s.storage.FindResource(gr)
+		if resource == nil || obj.UnmarshalJSON(body) != nil || obj.GetName() == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		_, found, err := resource.GetObject(r.Context(), types.NamespacedName{Namespace: namespace, Name: obj.GetName()})
+		if err != nil {
+			crdvalidation.WriteStatus(w, apierrors.NewInternalError(err))
+			return
+		}
+		if found {
+			crdvalidation.WriteStatus(w, apierrors.NewAlreadyExists(gr, obj.GetName()))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// collectionPath returns the resource and namespace of a path to a collection
+// of objects, like /api/v1/namespaces/<namespace>/pods or
+// /apis/<group>/<version>/<resource>.
+func collectionPath(path string) (schema.GroupResource, string, bool) {
+	tokens := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(tokens) == 3 && tokens[0] == "api":
+		return schema.GroupResource{Resource: tokens[2]}, "", true
+	case len(tokens) == 5 && tokens[0] == "api" && tokens[2] == "namespaces":
+		return schema.GroupResource{Resource: tokens[4]}, tokens[3], true
+	case len(tokens) == 4 && tokens[0] == "apis":
+		return schema.GroupResource{Group: tokens[1], Resource: tokens[3]}, "", true
+	case len(tokens) == 6 && tokens[0] == "apis" && tokens[3] == "namespaces":
+		return schema.GroupResource{Group: tokens[1], Resource: tokens[5]}, tokens[4], true
+	}
+
+	return schema.GroupResource{}, "", false
+}
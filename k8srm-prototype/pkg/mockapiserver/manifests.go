@@ -0,0 +1,130 @@
+package mockapiserver
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// ReadManifests reads the objects in a YAML or JSON file, which may contain
+// several documents, such as the output of `schedule gen-example`. If the path
+// is a directory, the .yaml, .yml and .json files in it are read in order of
+// their names. Subdirectories are not read.
+func ReadManifests(path string) ([]*unstructured.Unstructured, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return readManifestFile(path)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+
+	var objs []*unstructured.Unstructured
+	for _, file := range files {
+		fileObjs, err := readManifestFile(file)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, fileObjs...)
+	}
+
+	return objs, nil
+}
+
+func readManifestFile(path string) ([]*unstructured.Unstructured, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []*unstructured.Unstructured
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		// Objects are decoded from JSON, so that numbers are decoded as
+		// they are by the API server.
+		b, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		// Empty documents, like the one before the first separator in
+		// the output of gen-example, are skipped.
+		if len(bytes.TrimSpace(b)) == 0 || string(bytes.TrimSpace(b)) == "null" {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(b); err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
+// WriteManifests writes the objects to a file as YAML documents, which can be
+// read again with ReadManifests. The file is replaced atomically, so that it
+// is not left half written if writing fails.
+func WriteManifests(path string, objs []*unstructured.Unstructured) error {
+	var buf bytes.Buffer
+	for _, obj := range objs {
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return fmt.Errorf("error encoding %s %s: %w", obj.GetKind(), objectName(obj), err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(b)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
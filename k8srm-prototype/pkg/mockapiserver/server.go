@@ -0,0 +1,335 @@
+// Package mockapiserver runs an in-memory mock API server that serves the
+// kinds used by this prototype: the built-in Kubernetes kinds, like pods and
+// nodes, and the devmgmtproto.k8s.io kinds, which are defined by creating
+// their CRDs and are validated against them. Drivers can define the kinds of
+// their configuration objects at runtime by creating CRDs for them, and
+// objects of those kinds are validated the same way.
+//
+// The server can be preloaded with objects from manifests, and its objects
+// can be written out again, so that its state can be saved and restored.
+package mockapiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/kubebuilder-declarative-pattern/mockkubeapiserver"
+	"sigs.k8s.io/kubebuilder-declarative-pattern/mockkubeapiserver/storage"
+	"sigs.k8s.io/kubebuilder-declarative-pattern/mockkubeapiserver/storage/memorystorage"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/config/crd"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/crdvalidation"
+)
+
+var (
+	crdGroupKind     = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	crdGroupResource = schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}
+)
+
+// Server is a mock API server. It is an http.Handler, and can be served with
+// any http.Server.
+type Server struct {
+	k8s       *mockkubeapiserver.MockKubeAPIServer
+	storage   storage.Storage
+	validator *crdvalidation.Validator
+	handler   http.Handler
+
+	// builtinCRDs contains the names of the CRDs that the server creates
+	// itself, which are left out of Objects.
+	builtinCRDs map[string]bool
+}
+
+// New returns a Server with the kinds of the prototype registered and no
+// other objects.
+func New() (*Server, error) {
+	// The devmgmtproto.k8s.io kinds are defined by creating their CRDs, as
+	// in a real cluster. The CRDs are generated from the types in pkg/api,
+	// so they have the same kinds and scopes as the Go types. The mock API
+	// server does not look at the schemas in them, so objects are validated
+	// against the CRDs before they reach it.
+	crds, err := crd.CustomResourceDefinitions()
+	if err != nil {
+		return nil, fmt.Errorf("error loading CRDs: %w", err)
+	}
+
+	if err := checkKinds(crds); err != nil {
+		return nil, err
+	}
+
+	validator, err := crdvalidation.NewValidator(crds...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating validator: %w", err)
+	}
+
+	// The built-in kinds are already known to the storage.
+	st, err := memorystorage.NewMemoryStorage(&storage.RealClock{}, &storage.RandomUIDGenerator{})
+	if err != nil {
+		return nil, err
+	}
+	st.AddStorageHook(&crdHook{validator: validator})
+
+	// The address is not used, as the server is served by the caller.
+	k8s, err := mockkubeapiserver.NewMockKubeAPIServer("", mockkubeapiserver.WithStorage(st))
+	if err != nil {
+		return nil, fmt.Errorf("error creating mock-apiserver: %w", err)
+	}
+
+	builtinCRDs := make(map[string]bool)
+	for _, crd := range crds {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+		if err != nil {
+			return nil, fmt.Errorf("error converting CRD %s: %w", crd.Name, err)
+		}
+
+		if err := k8s.AddObject(&unstructured.Unstructured{Object: u}); err != nil {
+			return nil, fmt.Errorf("error creating CRD %s: %w", crd.Name, err)
+		}
+		builtinCRDs[crd.Name] = true
+	}
+
+	s := &Server{
+		k8s:         k8s,
+		storage:     st,
+		validator:   validator,
+		builtinCRDs: builtinCRDs,
+	}
+	s.handler = validator.Handler(s.statusHandler(s.createHandler(k8s)))
+
+	return s, nil
+}
+
+// checkKinds returns an error if any kind in pkg/api has no CRD, which
+// happens when a type is added without running `make generate`.
+func checkKinds(crds []*apiextensionsv1.CustomResourceDefinition) error {
+	scheme := runtime.NewScheme()
+	if err := api.AddToScheme(scheme); err != nil {
+		return err
+	}
+
+	defined := make(map[schema.GroupVersionKind]bool)
+	for _, crd := range crds {
+		for _, version := range crd.Spec.Versions {
+			defined[schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}] = true
+		}
+	}
+
+	knownTypes := scheme.KnownTypes(api.SchemeGroupVersion)
+	for _, kind := range sortedKeys(knownTypes) {
+		// Lists, and the meta types the scheme adds to every group,
+		// are not objects and have no CRDs of their own.
+		if _, ok := reflect.New(knownTypes[kind]).Interface().(metav1.Object); !ok {
+			continue
+		}
+
+		gvk := api.SchemeGroupVersion.WithKind(kind)
+		if !defined[gvk] {
+			return fmt.Errorf("no CRD for %s in config/crd, run make generate", gvk)
+		}
+	}
+
+	return nil
+}
+
+// crdHook adds the schemas of the CRDs that are created or updated to the
+// validator, so that objects of the kinds that drivers define are validated
+// like the devmgmtproto.k8s.io kinds. The mock API server itself registers
+// the kinds.
+type crdHook struct {
+	validator *crdvalidation.Validator
+}
+
+func (h *crdHook) OnWatchEvent(ev *storage.WatchEvent) {
+	if ev.GroupKind() != crdGroupKind {
+		return
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(ev.Unstructured().Object, crd); err != nil {
+		klog.Warningf("error decoding CRD %s: %v", ev.Unstructured().GetName(), err)
+		return
+	}
+
+	// A CRD that cannot be validated against is still served, as the
+	// mock API server accepts it either way.
+	if err := h.validator.AddCRD(crd); err != nil {
+		klog.Warningf("objects of CRD %s will not be validated: %v", crd.Name, err)
+	}
+}
+
+// ServeHTTP serves the API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// AddObjects creates the objects, in order, without going through the API.
+// As with `kubectl apply`, an object that already exists is replaced. The
+// objects are validated the same way as those created through the API, and
+// the first invalid object is an error. The UID and creation timestamp of the
+// objects are kept if they are set, so that objects written by Objects can be
+// restored along with the references between them.
+func (s *Server) AddObjects(objs []*unstructured.Unstructured) error {
+	for _, obj := range objs {
+		if err := s.addObject(context.Background(), obj.DeepCopy()); err != nil {
+			return fmt.Errorf("error creating %s %s: %w", obj.GetKind(), objectName(obj), err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) addObject(ctx context.Context, obj *unstructured.Unstructured) error {
+	for _, path := range s.validator.Prune(obj) {
+		klog.Warningf("%s %s: dropping unknown field %q", obj.GetKind(), objectName(obj), path)
+	}
+
+	if errs := s.validator.Validate(obj); len(errs) > 0 {
+		return apierrors.NewInvalid(obj.GroupVersionKind().GroupKind(), obj.GetName(), errs)
+	}
+
+	resource := s.findResource(obj.GroupVersionKind())
+	if resource == nil {
+		return fmt.Errorf("unknown kind %s", obj.GroupVersionKind())
+	}
+
+	id := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	existing, found, err := resource.GetObject(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	uid, created := obj.GetUID(), obj.GetCreationTimestamp()
+	if found {
+		if uid == "" {
+			obj.SetUID(existing.GetUID())
+		}
+		if created.IsZero() {
+			obj.SetCreationTimestamp(existing.GetCreationTimestamp())
+		}
+		return resource.UpdateObject(ctx, id, obj)
+	}
+
+	if err := s.k8s.AddObject(obj); err != nil {
+		return err
+	}
+
+	if uid == "" && created.IsZero() {
+		return nil
+	}
+
+	// The storage assigns a new UID and creation timestamp to every object
+	// it creates, so the originals are put back with an update.
+	restored := obj.DeepCopy()
+	if uid != "" {
+		restored.SetUID(uid)
+	}
+	if !created.IsZero() {
+		restored.SetCreationTimestamp(created)
+	}
+
+	return resource.UpdateObject(ctx, id, restored)
+}
+
+func (s *Server) findResource(gvk schema.GroupVersionKind) storage.ResourceInfo {
+	for _, r := range s.storage.AllResources() {
+		if r.Group == gvk.Group && r.Version == gvk.Version && r.Kind == gvk.Kind {
+			return s.storage.FindResource(schema.GroupResource{Group: r.Group, Resource: r.Name})
+		}
+	}
+
+	return nil
+}
+
+// Objects returns all of the objects in the server, except for the CRDs that
+// the server creates itself. CRDs come first, then namespaces, so that the
+// objects can be passed to AddObjects as they are. The rest are sorted by
+// kind, namespace and name.
+func (s *Server) Objects(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	listed := make(map[schema.GroupResource]bool)
+	for _, r := range s.storage.AllResources() {
+		// Resources may be served in more than one version, and CRDs
+		// that are updated are registered again, but their objects are
+		// stored only once.
+		gr := schema.GroupResource{Group: r.Group, Resource: r.Name}
+		if listed[gr] {
+			continue
+		}
+		listed[gr] = true
+
+		resource := s.storage.FindResource(gr)
+		if resource == nil {
+			continue
+		}
+
+		list, err := resource.ListObjects(ctx, storage.ListFilter{})
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s: %w", r.Name, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if obj.GroupVersionKind().GroupKind() == crdGroupKind && s.builtinCRDs[obj.GetName()] {
+				continue
+			}
+			objs = append(objs, obj)
+		}
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		pi, pj := priority(objs[i]), priority(objs[j])
+		if pi != pj {
+			return pi < pj
+		}
+
+		ki, kj := objs[i].GetAPIVersion()+"/"+objs[i].GetKind(), objs[j].GetAPIVersion()+"/"+objs[j].GetKind()
+		if ki != kj {
+			return ki < kj
+		}
+
+		return objectName(objs[i]) < objectName(objs[j])
+	})
+
+	return objs, nil
+}
+
+// priority orders the kinds that other objects depend on first.
+func priority(obj *unstructured.Unstructured) int {
+	switch obj.GroupVersionKind().GroupKind() {
+	case crdGroupKind:
+		return 0
+	case schema.GroupKind{Kind: "Namespace"}:
+		return 1
+	}
+
+	return 2
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func objectName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
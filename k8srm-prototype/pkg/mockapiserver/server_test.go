@@ -0,0 +1,320 @@
+package mockapiserver
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned"
+)
+
+const genExample = `---
+apiVersion: devmgmtproto.k8s.io/v1alpha1
+kind: DevicePool
+metadata:
+  creationTimestamp: null
+  name: shape-zero-00-foozer-00
+spec:
+  attributes:
+  - name: model
+    stringValue: foozer-1000
+  count: 8
+  driver: example.com-foozer
+  nodeName: shape-zero-00
+status:
+  availableDevices: 8
+`
+
+func TestReadManifests(t *testing.T) {
+	objs, err := ReadManifests("../../testdata")
+	require.NoError(t, err)
+
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.GetKind()+" "+objectName(obj))
+	}
+	require.Contains(t, names, "DeviceDriver example.com-foozer")
+	require.Contains(t, names, "DeviceClass example.com-foozer-single")
+	require.Contains(t, names, "Pod default/embedded-foozer-claim")
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "pools.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(genExample), 0644))
+
+	objs, err = ReadManifests(file)
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	require.Equal(t, int64(8), objs[0].Object["spec"].(map[string]any)["count"])
+}
+
+func TestKinds(t *testing.T) {
+	server, err := New()
+	require.NoError(t, err)
+
+	// Every kind is served once, with the scope of its Go type.
+	namespaced := make(map[schema.GroupKind][]bool)
+	for _, r := range server.storage.AllResources() {
+		gk := schema.GroupKind{Group: r.Group, Kind: r.Kind}
+		namespaced[gk] = append(namespaced[gk], r.Namespaced)
+	}
+
+	testCases := map[string]struct {
+		kind       schema.GroupKind
+		namespaced bool
+	}{
+		"DeviceDriver":          {kind: api.Kind("DeviceDriver")},
+		"DeviceClass":           {kind: api.Kind("DeviceClass")},
+		"DevicePool":            {kind: api.Kind("DevicePool")},
+		"DeviceClaim":           {kind: api.Kind("DeviceClaim"), namespaced: true},
+		"DevicePrivilegedClaim": {kind: api.Kind("DevicePrivilegedClaim"), namespaced: true},
+		"DeviceSetClaim":        {kind: api.Kind("DeviceSetClaim"), namespaced: true},
+		"DeviceClaimTemplate":   {kind: api.Kind("DeviceClaimTemplate"), namespaced: true},
+		"Pod":                   {kind: schema.GroupKind{Kind: "Pod"}, namespaced: true},
+		"Node":                  {kind: schema.GroupKind{Kind: "Node"}},
+		"ConfigMap":             {kind: schema.GroupKind{Kind: "ConfigMap"}, namespaced: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, []bool{tc.namespaced}, namespaced[tc.kind])
+		})
+	}
+}
+
+func TestDriverConfigCRD(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := New()
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client, err := dynamic.NewForConfig(&rest.Config{Host: httpServer.URL})
+	require.NoError(t, err)
+
+	// A driver defines the kind of its configuration objects by creating
+	// a CRD through the API.
+	objs, err := ReadManifests("../../testdata/foozer-crd.yaml")
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+
+	crds := client.Resource(schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"})
+	_, err = crds.Create(ctx, objs[0], metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	configs := client.Resource(schema.GroupVersionResource{Group: "foozer.example.com", Version: "v1alpha1", Resource: "foozerconfigs"}).Namespace("default")
+	config := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "foozer.example.com/v1alpha1",
+		"kind":       "FoozerConfig",
+		"metadata":   map[string]any{"name": "superfast-mode", "namespace": "default"},
+		"spec":       map[string]any{"superfast": "yes"},
+	}}
+
+	// Objects of the kind are validated against the CRD.
+	_, err = configs.Create(ctx, config, metav1.CreateOptions{})
+	require.True(t, apierrors.IsInvalid(err), "expected an invalid error, got %v", err)
+	require.EqualError(t, err, `FoozerConfig.foozer.example.com "superfast-mode" is invalid: spec.superfast: Invalid value: "string": spec.superfast in body must be of type boolean: "string"`)
+
+	config.Object["spec"] = map[string]any{"superfast": true}
+	_, err = configs.Create(ctx, config, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	list, err := configs.List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+}
+
+func TestClusterScopedStatus(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := New()
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client, err := versioned.NewForConfig(&rest.Config{Host: httpServer.URL})
+	require.NoError(t, err)
+	classes := client.DevmgmtprotoV1alpha1().DeviceClasses()
+
+	class, err := classes.Create(ctx, &api.DeviceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+		Spec:       api.DeviceClassSpec{DeviceType: "gpu"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// Only the status is updated through the status subresource.
+	class.Spec.DeviceType = "sriov-nic"
+	class.Status.Drivers = []string{"example.com-foozer"}
+	_, err = classes.UpdateStatus(ctx, class, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	class, err = classes.Get(ctx, "gpu", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "gpu", class.Spec.DeviceType)
+	require.Equal(t, []string{"example.com-foozer"}, class.Status.Drivers)
+
+	class.Name = "missing"
+	_, err = classes.UpdateStatus(ctx, class, metav1.UpdateOptions{})
+	require.True(t, apierrors.IsNotFound(err), "expected a not found error, got %v", err)
+
+	// An update of an older version of the class is a conflict.
+	class, err = classes.Get(ctx, "gpu", metav1.GetOptions{})
+	require.NoError(t, err)
+	stale := class.DeepCopy()
+	class.Status.Drivers = []string{"example.com-barzer"}
+	_, err = classes.UpdateStatus(ctx, class, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	stale.Status.Drivers = []string{"example.com-quxzer"}
+	_, err = classes.UpdateStatus(ctx, stale, metav1.UpdateOptions{})
+	require.True(t, apierrors.IsConflict(err), "expected a conflict error, got %v", err)
+
+	class, err = classes.Get(ctx, "gpu", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"example.com-barzer"}, class.Status.Drivers)
+}
+
+func TestClusterScopedStatusWithoutSubresource(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := New()
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client, err := dynamic.NewForConfig(&rest.Config{Host: httpServer.URL})
+	require.NoError(t, err)
+	drivers := client.Resource(api.SchemeGroupVersion.WithResource("devicedrivers"))
+
+	driver := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion":  api.DevMgmtAPIVersion,
+		"kind":        "DeviceDriver",
+		"metadata":    map[string]any{"name": "example.com-foozer"},
+		"deviceTypes": []any{"gpu"},
+	}}
+	driver, err = drivers.Create(ctx, driver, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// The DeviceDriver CRD has no status subresource.
+	driver.Object["status"] = map[string]any{"ready": true}
+	_, err = drivers.UpdateStatus(ctx, driver, metav1.UpdateOptions{})
+	require.True(t, apierrors.IsNotFound(err), "expected a not found error, got %v", err)
+}
+
+func TestCreateAlreadyExists(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := New()
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client, err := versioned.NewForConfig(&rest.Config{Host: httpServer.URL})
+	require.NoError(t, err)
+
+	claim := &api.DeviceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "default"},
+		Spec:       api.DeviceClaimSpec{DeviceClass: "gpu"},
+	}
+	_, err = client.DevmgmtprotoV1alpha1().DeviceClaims("default").Create(ctx, claim, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	_, err = client.DevmgmtprotoV1alpha1().DeviceClaims("default").Create(ctx, claim, metav1.CreateOptions{})
+	require.True(t, apierrors.IsAlreadyExists(err), "expected an already exists error, got %v", err)
+
+	// The same name may be used in another namespace.
+	claim.Namespace = "other"
+	_, err = client.DevmgmtprotoV1alpha1().DeviceClaims("other").Create(ctx, claim, metav1.CreateOptions{})
+	require.NoError(t, err)
+}
+
+func TestAddObjects(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := New()
+	require.NoError(t, err)
+
+	objs, err := ReadManifests("../../testdata")
+	require.NoError(t, err)
+	require.NoError(t, server.AddObjects(objs))
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	client, err := versioned.NewForConfig(&rest.Config{Host: httpServer.URL})
+	require.NoError(t, err)
+
+	drivers, err := client.DevmgmtprotoV1alpha1().DeviceDrivers().List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, drivers.Items, 4)
+
+	// Objects that are added are validated like those created through the
+	// API.
+	invalid := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "devmgmtproto.k8s.io/v1alpha1",
+		"kind":       "DeviceClaim",
+		"metadata":   map[string]any{"name": "invalid", "namespace": "default"},
+		"spec": map[string]any{
+			"deviceClass":    "gpu",
+			"minDeviceCount": int64(2),
+			"maxDeviceCount": int64(1),
+		},
+	}}
+	err = server.AddObjects([]*unstructured.Unstructured{invalid})
+	require.EqualError(t, err, `error creating DeviceClaim default/invalid: DeviceClaim.devmgmtproto.k8s.io "invalid" is invalid: spec: Invalid value: "object": minDeviceCount must not be greater than maxDeviceCount`)
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := New()
+	require.NoError(t, err)
+
+	objs, err := ReadManifests("../../testdata")
+	require.NoError(t, err)
+	require.NoError(t, server.AddObjects(objs))
+
+	// Objects that appear in more than one file are only saved once. The
+	// CRD of FoozerConfig is saved, unlike those the server creates itself.
+	saved, err := server.Objects(ctx)
+	require.NoError(t, err)
+	require.Len(t, saved, 23)
+	require.Equal(t, "foozerconfigs.foozer.example.com", saved[0].GetName())
+
+	file := filepath.Join(t.TempDir(), "snapshot.yaml")
+	require.NoError(t, WriteManifests(file, saved))
+
+	loaded, err := ReadManifests(file)
+	require.NoError(t, err)
+
+	restoredServer, err := New()
+	require.NoError(t, err)
+	require.NoError(t, restoredServer.AddObjects(loaded))
+
+	restored, err := restoredServer.Objects(ctx)
+	require.NoError(t, err)
+	require.Len(t, restored, len(saved))
+
+	// Everything but the resource versions is restored, so that owner
+	// references by UID still work.
+	for i := range saved {
+		saved[i].SetResourceVersion("")
+		restored[i].SetResourceVersion("")
+		require.Equal(t, saved[i], restored[i])
+	}
+}
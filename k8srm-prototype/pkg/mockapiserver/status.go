@@ -0,0 +1,137 @@
+package mockapiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/crdvalidation"
+)
+
+// statusHandler serves updates of the status of cluster-scoped custom
+// resources, like DeviceClasses, which the mock API server only serves for
+// namespaced ones. As with those, only the status of the object is replaced.
+// As in a real API server, kinds whose CRD has no status subresource are not
+// found, and an update with a stale resourceVersion is a conflict. Other
+// requests are passed on to next.
+func (s *Server) statusHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /apis/<group>/<version>/<resource>/<name>/status
+		tokens := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if r.Method != http.MethodPut || len(tokens) != 6 || tokens[0] != "apis" || tokens[5] != "status" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gr := schema.GroupResource{Group: tokens[1], Resource: tokens[3]}
+		name := tokens[4]
+
+		resource := s.storage.FindResource(gr)
+		if resource == nil {
+			crdvalidation.WriteStatus(w, apierrors.NewNotFound(gr, name))
+			return
+		}
+
+		hasStatus, err := s.hasStatusSubresource(r, gr, tokens[2])
+		if err != nil {
+			crdvalidation.WriteStatus(w, apierrors.NewInternalError(err))
+			return
+		}
+		if !hasStatus {
+			crdvalidation.WriteStatus(w, apierrors.NewNotFound(schema.GroupResource{Group: gr.Group, Resource: gr.Resource + "/status"}, name))
+			return
+		}
+
+		id := types.NamespacedName{Name: name}
+		existing, found, err := resource.GetObject(r.Context(), id)
+		if err != nil {
+			crdvalidation.WriteStatus(w, apierrors.NewInternalError(err))
+			return
+		}
+		if !found {
+			crdvalidation.WriteStatus(w, apierrors.NewNotFound(gr, name))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			crdvalidation.WriteStatus(w, apierrors.NewBadRequest(fmt.Sprintf("error reading body: %v", err)))
+			return
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(body); err != nil {
+			crdvalidation.WriteStatus(w, apierrors.NewBadRequest(fmt.Sprintf("error decoding body: %v", err)))
+			return
+		}
+		if obj.Object["status"] == nil {
+			crdvalidation.WriteStatus(w, apierrors.NewBadRequest("status not specified on status subresource update"))
+			return
+		}
+		if rv := obj.GetResourceVersion(); rv != "" && rv != existing.GetResourceVersion() {
+			crdvalidation.WriteStatus(w, apierrors.NewConflict(gr, name, errors.New(optimisticLockErrorMsg)))
+			return
+		}
+
+		// As with other updates, a no-op does not change the resource
+		// version, so that it does not trigger watches.
+		updated := existing.DeepCopy()
+		updated.Object["status"] = obj.Object["status"]
+		if !reflect.DeepEqual(existing, updated) {
+			if err := resource.UpdateObject(r.Context(), id, updated); err != nil {
+				crdvalidation.WriteStatus(w, apierrors.NewInternalError(err))
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated.Object)
+	})
+}
+
+// optimisticLockErrorMsg is the message of the conflict error that a real API
+// server returns for an update with a stale resourceVersion.
+const optimisticLockErrorMsg = "the object has been modified; please apply your changes to the latest version and try again"
+
+// hasStatusSubresource checks whether the version of the resource has a status
+// subresource, according to its CRD. Resources without a CRD are built into
+// the mock API server, and are assumed to have one.
+func (s *Server) hasStatusSubresource(r *http.Request, gr schema.GroupResource, version string) (bool, error) {
+	crds := s.storage.FindResource(crdGroupResource)
+	if crds == nil {
+		return true, nil
+	}
+
+	// CRDs are named after the resource and group they define.
+	u, found, err := crds.GetObject(r.Context(), types.NamespacedName{Name: gr.Resource + "." + gr.Group})
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return true, nil
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, crd); err != nil {
+		return false, fmt.Errorf("error decoding CRD %s: %w", u.GetName(), err)
+	}
+
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version {
+			return v.Subresources != nil && v.Subresources.Status != nil, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,513 @@
+// Package podscheduler schedules a Pod against an API server: it reads the
+// Pod, its device claims, and the classes, drivers, pools, and nodes, selects
+// a node with the schedule package, and writes the allocations to the
+// DeviceClaims, DeviceSetClaims, and DevicePrivilegedClaims of the Pod, and
+// the node name to the Pod.
+package podscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api/validation"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/client/clientset/versioned"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/schedule"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// Pods and Nodes are read with the dynamic client, since the Pods have fields
+// that are not part of the real PodSpec. The device management types have a
+// typed client.
+var (
+	podGVR  = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	nodeGVR = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+)
+
+// podClaim tracks a DeviceClaim needed by the Pod, and whether it already
+// exists in the API server or must be created when writing the results.
+type podClaim struct {
+	claim  api.DeviceClaim
+	create bool
+}
+
+// podClaims are the claims needed by the Pod, by kind. Each kind is in the
+// order of the device claims of the Pod. Only DeviceClaims can be embedded or
+// referenced by name, so the other kinds are always generated from templates.
+type podClaims struct {
+	claims           []podClaim
+	setClaims        []api.DeviceSetClaim
+	privilegedClaims []api.DevicePrivilegedClaim
+}
+
+// ErrUnschedulable is returned by SchedulePod if no node can satisfy the device
+// claims of the Pod.
+var ErrUnschedulable = errors.New("no node can satisfy the device claims")
+
+// Clients holds the clients needed to schedule a Pod.
+type Clients struct {
+	Dynamic dynamic.Interface
+	DevMgmt versioned.Interface
+}
+
+// NewClients returns the clients for the API server in the config.
+func NewClients(config *rest.Config) (*Clients, error) {
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	devMgmtClient, err := versioned.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Clients{Dynamic: dynamicClient, DevMgmt: devMgmtClient}, nil
+}
+
+// Result is the outcome of scheduling a Pod.
+type Result struct {
+	// NodeName is the node that the Pod was scheduled to.
+	NodeName string
+
+	// Claims are the DeviceClaims of the Pod, in the order of its device
+	// claims, as they were written with their allocations.
+	Claims []api.DeviceClaim
+
+	// SetClaims and PrivilegedClaims are the DeviceSetClaims and
+	// DevicePrivilegedClaims of the Pod, in the same way.
+	SetClaims        []api.DeviceSetClaim
+	PrivilegedClaims []api.DevicePrivilegedClaim
+
+	// EffectiveClaims are the DeviceClaims of the Pod resolved against
+	// their classes.
+	EffectiveClaims []api.DeviceClaim
+
+	// NodeResults are the results of trying to satisfy the claims on each
+	// node.
+	NodeResults []schedule.NodeResult
+}
+
+// SchedulePod fetches the Pod and everything needed to schedule it, selects a
+// node, and then writes the results back to the claims and the Pod. The
+// options are passed on to schedule.SelectNodeForPod, after the labels of the
+// nodes.
+//
+// If no node can satisfy the claims, the error wraps ErrUnschedulable, and the
+// result is returned as well so that the reasons can be reported.
+//
+// The Pod is written last, so that if writing the results fails part way,
+// SchedulePod can simply be run again: claims created by the earlier attempt
+// are reused, and the allocations it recorded in them are replaced.
+func SchedulePod(ctx context.Context, client *Clients, namespace, name string, opts ...schedule.Option) (*Result, error) {
+	u, err := client.Dynamic.Resource(podGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting pod %s/%s: %w", namespace, name, err)
+	}
+
+	nodeName, _, err := unstructured.NestedString(u.Object, "spec", "nodeName")
+	if err != nil {
+		return nil, fmt.Errorf("error reading nodeName from pod %s/%s: %w", namespace, name, err)
+	}
+	if nodeName != "" {
+		return nil, fmt.Errorf("pod %s/%s is already scheduled to node %q", namespace, name, nodeName)
+	}
+
+	pdcs, err := PodDeviceClaims(u)
+	if err != nil {
+		return nil, err
+	}
+	if len(pdcs) == 0 {
+		return nil, fmt.Errorf("pod %s/%s has no device claims", namespace, name)
+	}
+
+	claims, err := resolvePodClaims(ctx, client.DevMgmt, u, pdcs)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := client.DevMgmt.DevmgmtprotoV1alpha1().DeviceClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing device classes: %w", err)
+	}
+
+	drivers, err := client.DevMgmt.DevmgmtprotoV1alpha1().DeviceDrivers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing device drivers: %w", err)
+	}
+
+	poolList, err := client.DevMgmt.DevmgmtprotoV1alpha1().DevicePools().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing device pools: %w", err)
+	}
+
+	pools, err := availablePools(ctx, client.DevMgmt, poolList.Items, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := client.Dynamic.Resource(nodeGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	nodeLabels := make(map[string]map[string]string)
+	for _, n := range nodes.Items {
+		nodeLabels[n.GetName()] = n.GetLabels()
+	}
+
+	var deviceClaims []api.DeviceClaim
+	for _, pc := range claims.claims {
+		deviceClaims = append(deviceClaims, pc.claim)
+	}
+
+	// Resolving the claims against their classes up front reports a bad
+	// claim once, rather than as the failure reason for every node.
+	effective, err := effectiveClaims(deviceClaims, classes.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append([]schedule.Option{schedule.WithNodeLabels(nodeLabels)}, opts...)
+	nr, results := schedule.SelectNodeForPod(schedule.PodClaims{
+		Claims:           deviceClaims,
+		SetClaims:        claims.setClaims,
+		PrivilegedClaims: claims.privilegedClaims,
+	}, classes.Items, drivers.Items, pools, poolList.Items, opts...)
+
+	result := &Result{
+		EffectiveClaims: effective,
+		NodeResults:     results,
+	}
+
+	if nr == nil {
+		return result, fmt.Errorf("%w for pod %s/%s", ErrUnschedulable, namespace, name)
+	}
+
+	// The results are in the order of the ordinary claims, the members of
+	// each set claim, and then the privileged claims.
+	dcrs := nr.DeviceClaimResults
+	for _, pc := range claims.claims {
+		pc.claim.Status.Allocations = dcrs[0].Allocations()
+		dcrs = dcrs[1:]
+		if !slices.Contains(pc.claim.Status.PodNames, name) {
+			pc.claim.Status.PodNames = append(pc.claim.Status.PodNames, name)
+		}
+		written, err := writeClaim(ctx, client.DevMgmt, pc)
+		if err != nil {
+			return nil, err
+		}
+		result.Claims = append(result.Claims, *written)
+	}
+
+	for _, setClaim := range claims.setClaims {
+		setClaim.Status.ClaimStatus = nil
+		for range setClaim.Spec.ClaimSpec {
+			setClaim.Status.ClaimStatus = append(setClaim.Status.ClaimStatus, api.DeviceClaimStatus{
+				Allocations: dcrs[0].Allocations(),
+				PodNames:    []string{name},
+			})
+			dcrs = dcrs[1:]
+		}
+		written, err := client.DevMgmt.DevmgmtprotoV1alpha1().DeviceSetClaims(namespace).UpdateStatus(ctx, &setClaim, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error writing status of device set claim %s/%s: %w", namespace, setClaim.Name, err)
+		}
+		result.SetClaims = append(result.SetClaims, *written)
+	}
+
+	for _, privilegedClaim := range claims.privilegedClaims {
+		privilegedClaim.Status.Allocations = dcrs[0].Allocations()
+		dcrs = dcrs[1:]
+		if !slices.Contains(privilegedClaim.Status.PodNames, name) {
+			privilegedClaim.Status.PodNames = append(privilegedClaim.Status.PodNames, name)
+		}
+		written, err := client.DevMgmt.DevmgmtprotoV1alpha1().DevicePrivilegedClaims(namespace).UpdateStatus(ctx, &privilegedClaim, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error writing status of device privileged claim %s/%s: %w", namespace, privilegedClaim.Name, err)
+		}
+		result.PrivilegedClaims = append(result.PrivilegedClaims, *written)
+	}
+
+	if err := unstructured.SetNestedField(u.Object, nr.NodeName, "spec", "nodeName"); err != nil {
+		return nil, err
+	}
+
+	if _, err := client.Dynamic.Resource(podGVR).Namespace(namespace).Update(ctx, u, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("error updating pod %s/%s: %w", namespace, name, err)
+	}
+
+	result.NodeName = nr.NodeName
+
+	return result, nil
+}
+
+// effectiveClaims resolves each claim against its class.
+func effectiveClaims(claims []api.DeviceClaim, classes []api.DeviceClass) ([]api.DeviceClaim, error) {
+	var result []api.DeviceClaim
+	for i := range claims {
+		var class *api.DeviceClass
+		for j := range classes {
+			if classes[j].Name == claims[i].Spec.DeviceClass {
+				class = &classes[j]
+				break
+			}
+		}
+
+		ec, err := api.EffectiveClaim(&claims[i], class)
+		if err != nil {
+			return nil, fmt.Errorf("device claim %s/%s: %w", claims[i].Namespace, claims[i].Name, err)
+		}
+
+		result = append(result, *ec)
+	}
+
+	return result, nil
+}
+
+// PodDeviceClaims extracts the device claims from the unstructured Pod, since
+// they are not part of the real PodSpec, and validates them.
+func PodDeviceClaims(pod *unstructured.Unstructured) ([]api.PodDeviceClaim, error) {
+	raw, found, err := unstructured.NestedSlice(pod.Object, "spec", "deviceClaims")
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var result []api.PodDeviceClaim
+	fldPath := field.NewPath("spec", "deviceClaims")
+	for i, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("pod %s/%s: invalid deviceClaims entry: %v", pod.GetNamespace(), pod.GetName(), r)
+		}
+
+		var pdc api.PodDeviceClaim
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &pdc); err != nil {
+			return nil, fmt.Errorf("pod %s/%s: invalid deviceClaims entry: %w", pod.GetNamespace(), pod.GetName(), err)
+		}
+
+		if errs := validation.ValidatePodDeviceClaim(&pdc, fldPath.Index(i)); len(errs) > 0 {
+			return nil, fmt.Errorf("pod %s/%s: %w", pod.GetNamespace(), pod.GetName(), errs.ToAggregate())
+		}
+		result = append(result, pdc)
+	}
+
+	return result, nil
+}
+
+// resolvePodClaims gets the claims for the Pod device claims. Embedded claims
+// are given a name derived from the Pod, and will be created when the results
+// are written. Claims from templates are generated by the claim template
+// controller, with the same kind of name, and are of the kind of the spec in
+// the template.
+func resolvePodClaims(ctx context.Context, client versioned.Interface, pod *unstructured.Unstructured, pdcs []api.PodDeviceClaim) (*podClaims, error) {
+	namespace := pod.GetNamespace()
+
+	result := &podClaims{}
+	for _, pdc := range pdcs {
+		switch {
+		case pdc.DeviceClaimSpec != nil:
+			result.claims = append(result.claims, podClaim{
+				claim:  newPodClaim(pod, pdc.Name, *pdc.DeviceClaimSpec),
+				create: true,
+			})
+
+		case pdc.DeviceClaimName != nil:
+			claim, err := client.DevmgmtprotoV1alpha1().DeviceClaims(namespace).Get(ctx, *pdc.DeviceClaimName, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("error getting device claim %s/%s: %w", namespace, *pdc.DeviceClaimName, err)
+			}
+			if len(claim.Status.Allocations) > 0 && !slices.Equal(claim.Status.PodNames, []string{pod.GetName()}) {
+				return nil, fmt.Errorf("claim %s/%s is already allocated; sharing claims is not supported yet", namespace, claim.Name)
+			}
+			result.claims = append(result.claims, podClaim{claim: *claim})
+
+		case pdc.DeviceClaimTemplateName != nil:
+			template, err := client.DevmgmtprotoV1alpha1().DeviceClaimTemplates(namespace).Get(ctx, *pdc.DeviceClaimTemplateName, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("error getting device claim template %s/%s: %w", namespace, *pdc.DeviceClaimTemplateName, err)
+			}
+
+			name := api.PodClaimName(pod.GetName(), pdc.Name)
+			switch {
+			case template.Spec.DeviceSetClaimSpec != nil:
+				claim, err := client.DevmgmtprotoV1alpha1().DeviceSetClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+				if err := checkGeneratedClaim(pod, template, "device set claim", name, claim, err); err != nil {
+					return nil, err
+				}
+				result.setClaims = append(result.setClaims, *claim)
+
+			case template.Spec.DevicePrivilegedClaimSpec != nil:
+				claim, err := client.DevmgmtprotoV1alpha1().DevicePrivilegedClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+				if err := checkGeneratedClaim(pod, template, "device privileged claim", name, claim, err); err != nil {
+					return nil, err
+				}
+				result.privilegedClaims = append(result.privilegedClaims, *claim)
+
+			default:
+				claim, err := client.DevmgmtprotoV1alpha1().DeviceClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+				if err := checkGeneratedClaim(pod, template, "device claim", name, claim, err); err != nil {
+					return nil, err
+				}
+				result.claims = append(result.claims, podClaim{claim: *claim})
+			}
+
+		default:
+			return nil, fmt.Errorf("pod %s/%s: device claim %q must specify one of claim, claimName, or claimTemplateName", namespace, pod.GetName(), pdc.Name)
+		}
+	}
+
+	return result, nil
+}
+
+// checkGeneratedClaim checks the result of getting the claim of the kind that
+// the claim template controller generates for the Pod from the template.
+func checkGeneratedClaim(pod *unstructured.Unstructured, template *api.DeviceClaimTemplate, kind, name string, claim metav1.Object, err error) error {
+	namespace := pod.GetNamespace()
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%s %s/%s has not been generated from template %s yet; is the claim template controller running?", kind, namespace, name, template.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting %s %s/%s: %w", kind, namespace, name, err)
+	}
+	if !metav1.IsControlledBy(claim, pod) {
+		return fmt.Errorf("%s %s/%s was not generated for pod %s", kind, namespace, name, pod.GetName())
+	}
+
+	return nil
+}
+
+// newPodClaim returns a DeviceClaim controlled by the Pod, with the given
+// spec, so that it is deleted along with the Pod.
+func newPodClaim(pod *unstructured.Unstructured, claimName string, spec api.DeviceClaimSpec) api.DeviceClaim {
+	return api.DeviceClaim{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: api.DevMgmtAPIVersion,
+			Kind:       "DeviceClaim",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      api.PodClaimName(pod.GetName(), claimName),
+			Namespace: pod.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pod, pod.GroupVersionKind()),
+			},
+		},
+		Spec: spec,
+	}
+}
+
+// availablePools returns a copy of the DevicePools with their device counts
+// reduced by the allocations already recorded in DeviceClaims and
+// DeviceSetClaims, as SelectNodeForPod expects. DevicePrivilegedClaims do not
+// consume the devices. The allocations of the Pod's own claims are left out,
+// since they can only have been recorded by an earlier attempt to schedule the
+// Pod, and are replaced.
+func availablePools(ctx context.Context, client versioned.Interface, published []api.DevicePool, own *podClaims) ([]api.DevicePool, error) {
+	// An empty namespace lists the claims in all namespaces.
+	claims, err := client.DevmgmtprotoV1alpha1().DeviceClaims("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing device claims: %w", err)
+	}
+
+	setClaims, err := client.DevmgmtprotoV1alpha1().DeviceSetClaims("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing device set claims: %w", err)
+	}
+
+	skip := make(map[types.NamespacedName]bool)
+	for _, pc := range own.claims {
+		skip[types.NamespacedName{Namespace: pc.claim.Namespace, Name: pc.claim.Name}] = true
+	}
+	skipSets := make(map[types.NamespacedName]bool)
+	for _, sc := range own.setClaims {
+		skipSets[types.NamespacedName{Namespace: sc.Namespace, Name: sc.Name}] = true
+	}
+
+	allocated := make(map[string]int)
+	for _, c := range claims.Items {
+		if skip[types.NamespacedName{Namespace: c.Namespace, Name: c.Name}] {
+			continue
+		}
+
+		for _, a := range c.Status.Allocations {
+			allocated[a.DevicePoolName] += a.DeviceCount
+		}
+	}
+
+	for _, sc := range setClaims.Items {
+		if skipSets[types.NamespacedName{Namespace: sc.Namespace, Name: sc.Name}] {
+			continue
+		}
+
+		for _, cs := range sc.Status.ClaimStatus {
+			for _, a := range cs.Allocations {
+				allocated[a.DevicePoolName] += a.DeviceCount
+			}
+		}
+	}
+
+	pools := make([]api.DevicePool, len(published))
+	copy(pools, published)
+	for i := range pools {
+		pools[i].Spec.DeviceCount -= allocated[pools[i].Name]
+	}
+
+	return pools, nil
+}
+
+// writeClaim creates or updates the claim, and then writes its status
+// separately, since the API server ignores the status when the main resource
+// is written. A claim to be created that already exists is reused, if it was
+// created for the same Pod by an earlier attempt. It returns the claim as
+// written.
+func writeClaim(ctx context.Context, client versioned.Interface, pc podClaim) (*api.DeviceClaim, error) {
+	var written *api.DeviceClaim
+	var err error
+	claims := client.DevmgmtprotoV1alpha1().DeviceClaims(pc.claim.Namespace)
+	if pc.create {
+		written, err = claims.Create(ctx, &pc.claim, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			written, err = existingPodClaim(ctx, client, &pc.claim)
+		}
+	} else {
+		written, err = claims.Update(ctx, &pc.claim, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error writing claim %s/%s: %w", pc.claim.Namespace, pc.claim.Name, err)
+	}
+
+	written.Status = pc.claim.Status
+	written, err = claims.UpdateStatus(ctx, written, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error writing status of claim %s/%s: %w", pc.claim.Namespace, pc.claim.Name, err)
+	}
+
+	return written, nil
+}
+
+// existingPodClaim gets the existing claim with the name of the new claim, and
+// checks that it is controlled by the same Pod.
+func existingPodClaim(ctx context.Context, client versioned.Interface, claim *api.DeviceClaim) (*api.DeviceClaim, error) {
+	existing, err := client.DevmgmtprotoV1alpha1().DeviceClaims(claim.Namespace).Get(ctx, claim.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	owner, existingOwner := metav1.GetControllerOf(claim), metav1.GetControllerOf(existing)
+	if existingOwner == nil || existingOwner.UID != owner.UID {
+		return nil, fmt.Errorf("the claim already exists and was not created for pod %s", owner.Name)
+	}
+
+	return existing, nil
+}
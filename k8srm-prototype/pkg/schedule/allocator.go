@@ -0,0 +1,101 @@
+package schedule
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+)
+
+// Allocator implements an algorithm for selecting devices from the pools on a
+// node to satisfy a claim. Each allocator returns the candidate pool sets it
+// considered, and the best scoring one is chosen.
+type Allocator interface {
+	// Allocate tries to allocate between minCount and want devices for the
+	// claim, which is the effective claim, already resolved against its
+	// class. All the pools have available devices, and meet the driver and
+	// constraint requirements of the claim. Successful pool sets must be
+	// scored with the scorer.
+	Allocate(claim api.DeviceClaim, pools []api.DevicePool, minCount, want int, scorer Scorer) []PoolSetResult
+}
+
+// FlattenedAllocator treats the pools as just a set of individual devices,
+// rather than preferring to allocate whole pools. Conceptually, the node is
+// one more MatchAttributes value; since claims are evaluated a node at a time,
+// that comes for free here.
+//
+// The devices are split into groups that share the values of the claim's
+// MatchAttributes, and each group is a candidate. Within a group, devices
+// are taken from the pools with the most available devices first, which
+// tends to keep claims within as few pools as possible.
+type FlattenedAllocator struct{}
+
+var _ Allocator = FlattenedAllocator{}
+
+func (FlattenedAllocator) Allocate(claim api.DeviceClaim, pools []api.DevicePool, minCount, want int, scorer Scorer) []PoolSetResult {
+	var groupKeys []string
+	groups := make(map[string][]api.DevicePool)
+	for _, p := range pools {
+		key := matchAttributesKey(claim.Spec.MatchAttributes, p.Spec.Attributes)
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], p)
+	}
+
+	var results []PoolSetResult
+	for _, key := range groupKeys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Spec.DeviceCount > group[j].Spec.DeviceCount
+		})
+
+		psr := PoolSetResult{}
+		var used []api.DevicePool
+		remaining := want
+		for _, p := range group {
+			if remaining == 0 {
+				break
+			}
+
+			count := p.Spec.DeviceCount
+			if count > remaining {
+				count = remaining
+			}
+			remaining -= count
+
+			psr.PoolResults = append(psr.PoolResults, PoolResult{
+				PoolName:    p.Name,
+				DeviceCount: count,
+			})
+			used = append(used, p)
+		}
+
+		setAllocationScore(&psr, want-remaining, minCount, want)
+		if psr.Score > 0 {
+			psr.Score = clampScore(scorer.ScorePoolSet(claim, used, psr))
+		}
+
+		results = append(results, psr)
+	}
+
+	return results
+}
+
+// matchAttributesKey returns a string identifying the values of the named
+// attributes, such that values that are equal as by Attribute.Equal have the
+// same key, and different values do not. Each value is tagged with its type, as by
+// attributeString, and prefixed with its length, so that values containing
+// the separator cannot make different values produce the same key.
+func matchAttributesKey(names []string, attrs []api.Attribute) string {
+	var b strings.Builder
+	for _, name := range names {
+		value := attributeString(attrs, name)
+		b.WriteString(strconv.Itoa(len(value)))
+		b.WriteString(":")
+		b.WriteString(value)
+	}
+
+	return b.String()
+}
@@ -2,91 +2,69 @@ package schedule
 
 import (
 	"fmt"
-	"reflect"
 
-	"github.com/google/cel-go/cel"
 	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/cel"
 )
 
-const (
-	DeviceVarName = "device"
-)
-
-func MeetsConstraints(constraints *string, attrs []api.Attribute) (bool, error) {
-	if constraints == nil || *constraints == "" {
-		return true, nil
-	}
-
-	inputs := make(map[string]interface{})
-	inputs[DeviceVarName] = attributesToInputs(attrs)
-
-	return evalExpr(*constraints, inputs)
-}
-
-func attributesToInputs(attributes []api.Attribute) map[string]interface{} {
-	result := make(map[string]interface{}, len(attributes))
-
-	for _, a := range attributes {
-		if a.StringValue != nil {
-			result[a.Name] = *a.StringValue
-		} else if a.IntValue != nil {
-			result[a.Name] = *a.IntValue
-		} else if a.QuantityValue != nil {
-			result[a.Name] = *a.QuantityValue
-		} else if a.SemVerValue != nil {
-			result[a.Name] = *a.SemVerValue
-		}
+// CheckClassConstraints type-checks the constraints of the class against the
+// attribute schemas of the drivers that may satisfy it. If those drivers do
+// not all publish a schema, the constraints cannot be checked, and no error is
+// returned. An error is returned if no driver can satisfy the class.
+func CheckClassConstraints(class *api.DeviceClass, drivers []api.DeviceDriver) error {
+	claim := &api.DeviceClaim{Spec: api.DeviceClaimSpec{Driver: class.Spec.Driver}}
+	allowed, err := claimDrivers(claim, class, drivers)
+	if err != nil {
+		return err
 	}
 
-	return result
+	return checkConstraints("spec.constraints", class.Spec.Constraints, allowed, drivers)
 }
 
-func evalExpr(expr string, inputs map[string]interface{}) (bool, error) {
-	prog, err := compileExpr(expr)
+// CheckClaimConstraints type-checks the constraints of the claim against the
+// attribute schemas of the drivers that may satisfy it, like
+// CheckClassConstraints. An error is also returned if the claim cannot be
+// resolved against the class.
+func CheckClaimConstraints(claim *api.DeviceClaim, class *api.DeviceClass, drivers []api.DeviceDriver) error {
+	effective, err := api.EffectiveClaim(claim, class)
 	if err != nil {
-		return false, err
+		return err
 	}
 
-	val, _, err := prog.Eval(inputs)
+	allowed, err := claimDrivers(effective, class, drivers)
 	if err != nil {
-		return false, err
+		return err
 	}
 
-	result, err := val.ConvertToNative(reflect.TypeOf(true))
-	if err != nil {
-		return false, err
-	}
+	return checkConstraints("spec.constraints", claim.Spec.Constraints, allowed, drivers)
+}
 
-	s, ok := result.(bool)
-	if !ok {
-		return false, fmt.Errorf("expression returned non-string value: %v", result)
+// checkConstraints compiles the constraints using the schema of the allowed
+// drivers. Errors are prefixed with the path of the constraints field.
+func checkConstraints(path string, constraints *string, allowed map[string]bool, drivers []api.DeviceDriver) error {
+	if constraints == nil || *constraints == "" {
+		return nil
 	}
 
-	return s, nil
-}
-
-// compileExpr returns a compiled CEL expression.
-func compileExpr(expr string) (cel.Program, error) {
-	var opts []cel.EnvOption
-	opts = append(opts, cel.HomogeneousAggregateLiterals())
-	opts = append(opts, cel.EagerlyValidateDeclarations(true), cel.DefaultUTCTimeZone(true))
-	opts = append(opts, cel.Variable(DeviceVarName, cel.DynType))
+	var selected []api.DeviceDriver
+	for _, d := range drivers {
+		if allowed[d.Name] {
+			selected = append(selected, d)
+		}
+	}
 
-	env, err := cel.NewEnv(opts...)
+	schema, err := cel.AttributeSchemaForDrivers(selected)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("%s: %w", path, err)
 	}
 
-	ast, issues := env.Compile(expr)
-	if issues != nil {
-		return nil, issues.Err()
+	if schema == nil {
+		return nil
 	}
 
-	_, err = cel.AstToCheckedExpr(ast)
-	if err != nil {
-		return nil, err
+	if err := cel.CompileConstraints(*constraints, schema); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
 	}
-	return env.Program(ast,
-		cel.EvalOptions(cel.OptOptimize),
-	)
+
+	return nil
 }
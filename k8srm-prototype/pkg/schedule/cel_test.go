@@ -4,9 +4,10 @@ import (
 	"testing"
 
 	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/gen"
 	"github.com/stretchr/testify/require"
 
-	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func ptr[T any](val T) *T {
@@ -14,91 +15,159 @@ func ptr[T any](val T) *T {
 	return &v
 }
 
-func TestMeetsConstraints(t *testing.T) {
-	testCases := map[string]struct {
-		constraints *string
-		attrs       []api.Attribute
-		expErr      string
-		result      bool
-	}{
-		"nil constraint": {
-			constraints: nil,
-			result:      true,
+// schemaTestDrivers returns drivers that publish different attribute schemas
+// for the same device type, and one that publishes none.
+func schemaTestDrivers() []api.DeviceDriver {
+	return []api.DeviceDriver{
+		{
+			ObjectMeta:  metav1.ObjectMeta{Name: "example.com-foozer"},
+			DeviceTypes: []string{"gpu"},
+			AttributeSchema: []api.AttributeSchema{
+				{Name: "model", Type: api.AttributeTypeString},
+			},
 		},
-		"empty constraint": {
-			constraints: ptr(""),
-			result:      true,
+		{
+			ObjectMeta:  metav1.ObjectMeta{Name: "example.com-barzer"},
+			DeviceTypes: []string{"gpu"},
+			AttributeSchema: []api.AttributeSchema{
+				{Name: "model", Type: api.AttributeTypeString},
+				{Name: "numa", Type: api.AttributeTypeInt},
+			},
+		},
+		{
+			ObjectMeta:  metav1.ObjectMeta{Name: "sriov-nic"},
+			DeviceTypes: []string{"sriov-nic"},
 		},
-		"simple constraint met": {
-			constraints: ptr("device.vendor == 'example.com'"),
-			attrs: []api.Attribute{
-				{
-					Name:        "vendor",
-					StringValue: ptr("example.com"),
-				},
+	}
+}
+
+func TestCheckClassConstraints(t *testing.T) {
+	testCases := map[string]struct {
+		class  api.DeviceClassSpec
+		expErr string
+	}{
+		"valid for all drivers": {
+			class: api.DeviceClassSpec{
+				DeviceType:  "gpu",
+				Constraints: ptr("device.model == 'foozer-1000'"),
 			},
-			result: true,
 		},
-		"simple constraint failed": {
-			constraints: ptr("device.vendor == 'example.com'"),
-			attrs: []api.Attribute{
-				{
-					Name:        "vendor",
-					StringValue: ptr("example.org"),
-				},
+		"attribute from one driver": {
+			class: api.DeviceClassSpec{
+				DeviceType:  "gpu",
+				Driver:      ptr("example.com-foozer"),
+				Constraints: ptr("device.numa == 0"),
 			},
-			result: false,
+			expErr: "spec.constraints: ERROR: <input>:1:7: undefined field 'numa'",
 		},
-		"multi-attribute constraint met": {
-			constraints: ptr("device.vendor == 'example.com' && device.model == 'foozer-1000'"),
-			attrs: []api.Attribute{
-				{
-					Name:        "vendor",
-					StringValue: ptr("example.com"),
-				},
-				{
-					Name:        "model",
-					StringValue: ptr("foozer-1000"),
-				},
+		"driver without a schema": {
+			class: api.DeviceClassSpec{
+				DeviceType:  "sriov-nic",
+				Constraints: ptr("device.anything == 0"),
 			},
-			result: true,
 		},
-		"simple device and pool constraint failed": {
-			constraints: ptr("device.vendor == 'example.com' && device.model == 'foozer-1000'"),
-			attrs: []api.Attribute{
-				{
-					Name:        "vendor",
-					StringValue: ptr("example.org"),
-				},
-				{
-					Name:        "model",
-					StringValue: ptr("foozer-1000"),
-				},
+		"no driver for the device type": {
+			class: api.DeviceClassSpec{
+				DeviceType:  "vlan",
+				Constraints: ptr("device.anything == 0"),
 			},
-			result: false,
+			expErr: `no registered driver can satisfy device class "myclass"`,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			class := api.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclass"},
+				Spec:       tc.class,
+			}
+
+			err := CheckClassConstraints(&class, schemaTestDrivers())
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestCheckClaimConstraints(t *testing.T) {
+	class := &api.DeviceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "myclass"},
+		Spec: api.DeviceClassSpec{
+			DeviceType: "gpu",
+			Driver:     ptr("example.com-barzer"),
+		},
+	}
+
+	testCases := map[string]struct {
+		claim  api.DeviceClaimSpec
+		class  *api.DeviceClass
+		expErr string
+	}{
+		"valid": {
+			claim: api.DeviceClaimSpec{DeviceClass: "myclass", Constraints: ptr("device.numa == 0")},
+			class: class,
+		},
+		"unknown attribute": {
+			claim:  api.DeviceClaimSpec{DeviceClass: "myclass", Constraints: ptr("device.cores == 0")},
+			class:  class,
+			expErr: "spec.constraints: ERROR: <input>:1:7: undefined field 'cores'",
+		},
+		"class not found": {
+			claim:  api.DeviceClaimSpec{DeviceClass: "myclass", Constraints: ptr("device.numa == 0")},
+			expErr: `device class "myclass" not found`,
+		},
+		"driver does not match the class": {
+			claim:  api.DeviceClaimSpec{DeviceClass: "myclass", Driver: ptr("example.com-foozer"), Constraints: ptr("device.numa == 0")},
+			class:  class,
+			expErr: `claim driver "example.com-foozer" does not match class driver "example.com-barzer"`,
 		},
-		//TODO: add CEL type conversion for resource.Quantity so this test below can be enabled
-		"quantity constraint met": {
-			constraints: ptr("device.memory >= '10Gi'"),
-			attrs: []api.Attribute{
-				{
-					Name:          "memory",
-					QuantityValue: ptr(resource.MustParse("10Gi")),
-				},
+		"driver not registered": {
+			claim: api.DeviceClaimSpec{DeviceClass: "myclass", Constraints: ptr("device.numa == 0")},
+			class: &api.DeviceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclass"},
+				Spec:       api.DeviceClassSpec{DeviceType: "gpu", Driver: ptr("example.com-quxzer")},
 			},
-			expErr: "unsupported conversion to ref.Val: (resource.Quantity){{10737418240 0} {<nil>} 10Gi BinarySI}",
-			result: true,
+			expErr: `no registered driver can satisfy device class "myclass"`,
 		},
 	}
+
 	for tn, tc := range testCases {
 		t.Run(tn, func(t *testing.T) {
-			result, err := MeetsConstraints(tc.constraints, tc.attrs)
+			claim := api.DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "myclaim", Namespace: "default"},
+				Spec:       tc.claim,
+			}
+
+			err := CheckClaimConstraints(&claim, tc.class, schemaTestDrivers())
 			if tc.expErr == "" {
 				require.NoError(t, err)
-				require.Equal(t, tc.result, result)
 			} else {
-				require.EqualError(t, err, tc.expErr)
+				require.ErrorContains(t, err, tc.expErr)
 			}
 		})
 	}
 }
+
+func BenchmarkSelectNodeConstraints(b *testing.B) {
+	claims := []api.DeviceClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foozer-claim",
+				Namespace: "default",
+			},
+			Spec: api.DeviceClaimSpec{
+				DeviceClass:    "foozer-1000",
+				Constraints:    ptr("device.numa == '1'"),
+				MinDeviceCount: ptr(2),
+			},
+		},
+	}
+	pools := gen.GenFoozerBarzerNodes(1000)
+
+	for i := 0; i < b.N; i++ {
+		SelectNode(claims, testClasses(), testDrivers(), pools)
+	}
+}
@@ -0,0 +1,78 @@
+package schedule
+
+import (
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// poolsByNode collects the pools by node. Network attached pools, which have
+// no NodeName, are added to every node from which they may be reached. The
+// candidate nodes are those with local pools, along with any nodes for which
+// labels were provided.
+//
+// A network attached pool shows up under more than one node, but it is still
+// the same pool. Since only the allocations for the selected node are
+// returned, it is debited once, just like a local pool. When evaluating the
+// claims on a node, the allocations of each claim are applied to the shared
+// pool before evaluating the next one, as for any other pool.
+func poolsByNode(pools []api.DevicePool, nodeLabels map[string]map[string]string) map[string][]api.DevicePool {
+	result := make(map[string][]api.DevicePool)
+	for node := range nodeLabels {
+		result[node] = nil
+	}
+
+	var networkPools []api.DevicePool
+	for _, p := range pools {
+		if p.Spec.NodeName == nil || *p.Spec.NodeName == "" {
+			networkPools = append(networkPools, p)
+			continue
+		}
+
+		result[*p.Spec.NodeName] = append(result[*p.Spec.NodeName], p)
+	}
+
+	for _, np := range networkPools {
+		for node, nodePools := range result {
+			if reachable(np, nodeLabels[node], nodePools) {
+				result[node] = append(result[node], np)
+			}
+		}
+	}
+
+	return result
+}
+
+// reachable checks whether the network attached pool may be used from a node
+// with the given labels and local pools.
+func reachable(pool api.DevicePool, nodeLabels map[string]string, nodePools []api.DevicePool) bool {
+	if pool.Spec.NodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(pool.Spec.NodeSelector)
+		if err != nil || !selector.Matches(labels.Set(nodeLabels)) {
+			return false
+		}
+	}
+
+	if len(pool.Spec.ReachabilityAttributes) == 0 {
+		return true
+	}
+
+	if !hasAttributes(pool.Spec.Attributes, pool.Spec.ReachabilityAttributes) {
+		return false
+	}
+
+	key := matchAttributesKey(pool.Spec.ReachabilityAttributes, pool.Spec.Attributes)
+	for _, p := range nodePools {
+		if p.Spec.NodeName == nil || *p.Spec.NodeName == "" {
+			continue
+		}
+
+		if hasAttributes(p.Spec.Attributes, pool.Spec.ReachabilityAttributes) &&
+			matchAttributesKey(pool.Spec.ReachabilityAttributes, p.Spec.Attributes) == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package schedule
+
+// SolverMode selects how the claims are satisfied on each node.
+type SolverMode string
+
+const (
+	// SolverGreedy evaluates the claims in the order presented, taking the
+	// best pool set for each claim in turn.
+	SolverGreedy SolverMode = "greedy"
+
+	// SolverExhaustive first tries the greedy approach. If that fails, it
+	// searches over claim orderings and alternative pool sets for each
+	// claim, backtracking when a later claim cannot be satisfied.
+	SolverExhaustive SolverMode = "exhaustive"
+)
+
+// AllocationPolicy selects how many devices are allocated for a claim, when
+// the claim allows a range of device counts.
+type AllocationPolicy string
+
+const (
+	// AllocationPolicyMinimum allocates only the minimum number of
+	// devices for each claim.
+	AllocationPolicyMinimum AllocationPolicy = "minimum"
+
+	// AllocationPolicyMaximum allocates as many devices as possible for
+	// each claim, up to the maximum.
+	AllocationPolicyMaximum AllocationPolicy = "maximum"
+)
+
+const (
+	// DefaultSearchBudget is the default maximum number of claim
+	// evaluations the exhaustive solver may make for each node.
+	DefaultSearchBudget = 1000
+)
+
+// Option configures the behavior of SelectNode.
+type Option func(*options)
+
+type options struct {
+	solver           SolverMode
+	searchBudget     int
+	allocationPolicy AllocationPolicy
+	scorer           Scorer
+	allocator        Allocator
+	nodeLabels       map[string]map[string]string
+}
+
+func defaultOptions() *options {
+	return &options{
+		solver:           SolverGreedy,
+		searchBudget:     DefaultSearchBudget,
+		allocationPolicy: AllocationPolicyMinimum,
+		scorer:           FewestPoolsScorer{},
+		allocator:        PoolSetAllocator{},
+	}
+}
+
+func buildOptions(opts []Option) *options {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithSolver selects the solver used to satisfy the claims on each node.
+func WithSolver(mode SolverMode) Option {
+	return func(o *options) {
+		o.solver = mode
+	}
+}
+
+// WithSearchBudget limits the number of claim evaluations the exhaustive
+// solver may make for each node. Once the budget is spent, the search stops
+// and the greedy result for the node is used.
+func WithSearchBudget(budget int) Option {
+	return func(o *options) {
+		o.searchBudget = budget
+	}
+}
+
+// WithAllocationPolicy selects how many devices are allocated for claims that
+// allow a range of device counts.
+func WithAllocationPolicy(policy AllocationPolicy) Option {
+	return func(o *options) {
+		o.allocationPolicy = policy
+	}
+}
+
+// WithScorer selects the Scorer used to compare pool sets and nodes.
+func WithScorer(scorer Scorer) Option {
+	return func(o *options) {
+		o.scorer = scorer
+	}
+}
+
+// WithAllocator selects the Allocator used to select devices for each claim.
+func WithAllocator(allocator Allocator) Option {
+	return func(o *options) {
+		o.allocator = allocator
+	}
+}
+
+// WithNodeLabels provides the labels of each node, keyed by node name. These are
+// matched against the NodeSelector of network attached pools. Any nodes listed
+// here are also considered, even if they have no local pools.
+func WithNodeLabels(nodeLabels map[string]map[string]string) Option {
+	return func(o *options) {
+		o.nodeLabels = nodeLabels
+	}
+}
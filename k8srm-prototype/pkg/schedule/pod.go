@@ -0,0 +1,83 @@
+package schedule
+
+import (
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+)
+
+// PodClaims are the claims of a Pod, of each kind, which must all be satisfied
+// on the same node.
+type PodClaims struct {
+	Claims           []api.DeviceClaim
+	SetClaims        []api.DeviceSetClaim
+	PrivilegedClaims []api.DevicePrivilegedClaim
+}
+
+// SelectNodeForPod will select the node that can best satisfy all the claims
+// of a Pod. It works like SelectNode, and takes the same options, but also
+// satisfies DeviceSetClaims as SelectNodeForSet does, and DevicePrivilegedClaims
+// as AllocatePrivileged does.
+//
+// On each node, the ordinary claims are evaluated first, then each set claim
+// with the devices that remain, and finally the privileged claims. The
+// DeviceClaimResults of each node are in that order, with the members of each
+// set named as in SelectNodeForSet. The node is then scored again with all the
+// results, leaving out the privileged ones.
+//
+// The pools should have their available devices, as for SelectNode, while the
+// published pools should have the devices published by the drivers, as for
+// AllocatePrivileged. The published pools are only needed for privileged
+// claims.
+//
+// The returned values are the same as for SelectNode. The allocations of the
+// selected node do not include those of the privileged claims, which do not
+// consume the devices.
+func SelectNodeForPod(claims PodClaims, classes []api.DeviceClass, drivers []api.DeviceDriver, pools, published []api.DevicePool, opts ...Option) (*NodeResult, []NodeResult) {
+	o := buildOptions(opts)
+
+	classesByName := classesByName(classes)
+
+	var results []NodeResult
+	for node, nodeDevPools := range poolsByNode(pools, o.nodeLabels) {
+		results = append(results, evaluateNodeForPod(node, claims, classesByName, drivers, nodeDevPools, published, o))
+	}
+
+	best := bestNode(results)
+	if best == -1 {
+		return nil, results
+	}
+
+	return &results[best], results
+}
+
+func evaluateNodeForPod(node string, claims PodClaims, classes map[string]*api.DeviceClass, drivers []api.DeviceDriver, pools, published []api.DevicePool, o *options) NodeResult {
+	nr := evaluateNode(node, claims.Claims, classes, drivers, pools, o)
+	if len(claims.SetClaims) == 0 && len(claims.PrivilegedClaims) == 0 {
+		return nr
+	}
+
+	available := make([]api.DevicePool, len(pools))
+	copy(available, pools)
+	applyAllocations(available, nr.Allocations())
+
+	for _, setClaim := range claims.SetClaims {
+		snr := evaluateNodeForSet(node, setClaim.Spec.MatchAttributes, setMemberClaims(setClaim), classes, drivers, available, o)
+		nr.DeviceClaimResults = append(nr.DeviceClaimResults, snr.DeviceClaimResults...)
+		if snr.Solver == SolverExhaustive {
+			nr.Solver = SolverExhaustive
+		}
+
+		applyAllocations(available, snr.Allocations())
+	}
+
+	for _, privilegedClaim := range claims.PrivilegedClaims {
+		_, dcr := AllocatePrivileged(privilegedClaim, node, published)
+		nr.DeviceClaimResults = append(nr.DeviceClaimResults, dcr)
+	}
+
+	nr.NodeScore = 0
+	if nr.satisfied() {
+		nr.NodeScore = clampScore(o.scorer.ScoreNode(&nr, pools))
+	}
+
+	return nr
+}
@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+)
+
+// equivalentPools groups the pools that are interchangeable for the purposes
+// of allocation; that is, they have the same driver, attributes, and available
+// devices. The groups are in order of the first appearance of each kind of
+// pool.
+func equivalentPools(pools []api.DevicePool) [][]api.DevicePool {
+	var groups [][]api.DevicePool
+	index := make(map[string]int)
+	for _, p := range pools {
+		key := poolKey(p)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], p)
+	}
+
+	return groups
+}
+
+// poolKey returns a string identifying the driver, available devices, and
+// attributes of the pool. The driver and the attribute names are prefixed
+// with their lengths, and the values are as in matchAttributesKey, so that
+// pools with different attributes cannot have the same key.
+func poolKey(p api.DevicePool) string {
+	var names []string
+	for _, a := range p.Spec.Attributes {
+		names = append(names, a.Name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d:%s/%d/", len(p.Spec.Driver), p.Spec.Driver, p.Spec.DeviceCount)
+	for _, name := range names {
+		fmt.Fprintf(&b, "%d:%s", len(name), name)
+	}
+	b.WriteString("/")
+	b.WriteString(matchAttributesKey(names, p.Spec.Attributes))
+
+	return b.String()
+}
+
+// capacitySums returns, for each set size n, the sum of the available devices
+// in the n largest pools, at index n-1. This is an upper bound on the devices
+// any set of n pools could provide.
+func capacitySums(pools []api.DevicePool) []int {
+	counts := make([]int, len(pools))
+	for i, p := range pools {
+		counts[i] = p.Spec.DeviceCount
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+
+	sums := make([]int, len(counts))
+	sum := 0
+	for i, c := range counts {
+		sum += c
+		sums[i] = sum
+	}
+
+	return sums
+}
+
+// forEachPoolSet calls fn for each set of setSize pools, where sets that only
+// differ by swapping pools within a group are considered the same. The sets
+// are enumerated one at a time, in the same order as the pools, rather than
+// generating all of them up front.
+func forEachPoolSet(groups [][]api.DevicePool, setSize int, fn func([]api.DevicePool)) {
+	// remaining[i] is the number of pools in groups i and later
+	remaining := make([]int, len(groups)+1)
+	for i := len(groups) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + len(groups[i])
+	}
+
+	set := make([]api.DevicePool, 0, setSize)
+
+	var choose func(group, size int)
+	choose = func(group, size int) {
+		if size == 0 {
+			fn(set)
+			return
+		}
+
+		if group == len(groups) || remaining[group] < size {
+			return
+		}
+
+		// Take as many as possible from this group first, so that
+		// earlier pools come first.
+		most := len(groups[group])
+		if most > size {
+			most = size
+		}
+
+		for n := most; n >= 0; n-- {
+			set = append(set, groups[group][:n]...)
+			choose(group+1, size-n)
+			set = set[:len(set)-n]
+		}
+	}
+
+	choose(0, setSize)
+}
+
+// topPoolSetResults keeps the highest scoring pool set results, in order of
+// score. Results with equal scores are kept in the order they were added.
+type topPoolSetResults struct {
+	max     int
+	results []PoolSetResult
+}
+
+func newTopPoolSetResults(max int) *topPoolSetResults {
+	return &topPoolSetResults{max: max}
+}
+
+func (t *topPoolSetResults) add(psr PoolSetResult) {
+	i := sort.Search(len(t.results), func(i int) bool {
+		return t.results[i].Score < psr.Score
+	})
+
+	if i >= t.max {
+		return
+	}
+
+	if len(t.results) < t.max {
+		t.results = append(t.results, PoolSetResult{})
+	}
+
+	copy(t.results[i+1:], t.results[i:])
+	t.results[i] = psr
+}
+
+func (t *topPoolSetResults) list() []PoolSetResult {
+	return t.results
+}
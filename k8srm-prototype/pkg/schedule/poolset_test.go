@@ -0,0 +1,250 @@
+package schedule
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/gen"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// sameNode returns the pools with all of them moved onto one node, so that
+// pools that only differed by node become equivalent.
+func sameNode(pools []api.DevicePool, node string) []api.DevicePool {
+	for i := range pools {
+		pools[i].Name = fmt.Sprintf("%s-%03d", node, i)
+		pools[i].Spec.NodeName = &node
+	}
+
+	return pools
+}
+
+func TestForEachPoolSet(t *testing.T) {
+	// three numa 0 pools, and one numa 1 pool
+	pools := sameNode(append(gen.GenShapeZero(3), gen.GenShapeOne(1)[1]), "big-node")
+	groups := equivalentPools(pools)
+	require.Len(t, groups, 2)
+
+	var sets [][]string
+	forEachPoolSet(groups, 2, func(set []api.DevicePool) {
+		var names []string
+		for _, p := range set {
+			names = append(names, p.Name)
+		}
+		sets = append(sets, names)
+	})
+
+	require.Equal(t, [][]string{
+		{"big-node-000", "big-node-001"},
+		{"big-node-000", "big-node-003"},
+	}, sets)
+}
+
+func TestEquivalentPoolsDistinctValues(t *testing.T) {
+	pool := func(name string, attrs ...api.Attribute) api.DevicePool {
+		return api.DevicePool{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: api.DevicePoolSpec{
+				Driver:      "example.com-foozer",
+				DeviceCount: 1,
+				Attributes:  attrs,
+			},
+		}
+	}
+
+	testCases := map[string][]api.DevicePool{
+		"separators in values": {
+			pool("pool-a", api.Attribute{Name: "x", StringValue: ptr("1,y=s:2")}),
+			pool("pool-b", api.Attribute{Name: "x", StringValue: ptr("1")}, api.Attribute{Name: "y", StringValue: ptr("2")}),
+		},
+		"int and string values": {
+			pool("pool-a", api.Attribute{Name: "x", IntValue: ptr(1)}),
+			pool("pool-b", api.Attribute{Name: "x", StringValue: ptr("1")}),
+		},
+	}
+
+	for tn, pools := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			require.Len(t, equivalentPools(pools), 2)
+		})
+	}
+
+	// Equal quantities are equivalent, however they are written.
+	pools := []api.DevicePool{
+		pool("pool-a", api.Attribute{Name: "memory", QuantityValue: ptr(resource.MustParse("1Gi"))}),
+		pool("pool-b", api.Attribute{Name: "memory", QuantityValue: ptr(resource.MustParse("1073741824"))}),
+	}
+	require.Len(t, equivalentPools(pools), 1)
+}
+
+func TestTopPoolSetResults(t *testing.T) {
+	top := newTopPoolSetResults(3)
+	for i, score := range []int{0, 50, 100, 50, 100} {
+		top.add(PoolSetResult{
+			PoolResults: []PoolResult{{PoolName: fmt.Sprintf("pool-%d", i)}},
+			Score:       score,
+		})
+	}
+
+	var names []string
+	for _, psr := range top.list() {
+		names = append(names, psr.PoolResults[0].PoolName)
+	}
+	require.Equal(t, []string{"pool-2", "pool-4", "pool-1"}, names)
+}
+
+// distinctPools returns single device pools on one node, which all differ by
+// their index attribute, so that none of them are equivalent.
+func distinctPools(count int) []api.DevicePool {
+	var pools []api.DevicePool
+	for i := 0; i < count; i++ {
+		pools = append(pools, api.DevicePool{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pool-%02d", i)},
+			Spec: api.DevicePoolSpec{
+				NodeName:    ptr("node-00"),
+				Driver:      "example.com-foozer",
+				DeviceCount: 1,
+				Attributes: []api.Attribute{
+					{Name: "index", IntValue: ptr(i)},
+					{Name: "numa", IntValue: ptr(i % 2)},
+				},
+			},
+		})
+	}
+
+	return pools
+}
+
+func TestPoolSetAllocatorMaximum(t *testing.T) {
+	testCases := map[string]struct {
+		pools           int
+		want            int
+		matchAttributes []string
+		expectCount     int
+		expectScore     int
+	}{
+		"want all the pools": {
+			pools:       24,
+			want:        24,
+			expectCount: 24,
+			expectScore: 77,
+		},
+		"want more than the pools": {
+			pools:       24,
+			want:        100,
+			expectCount: 24,
+			expectScore: 1,
+		},
+		"match attributes": {
+			pools:           8,
+			want:            8,
+			matchAttributes: []string{"numa"},
+			expectCount:     4,
+			expectScore:     47,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			claim := api.DeviceClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "foozer-claim", Namespace: "default"},
+				Spec:       api.DeviceClaimSpec{MatchAttributes: tc.matchAttributes},
+			}
+
+			results := PoolSetAllocator{}.Allocate(claim, distinctPools(tc.pools), 1, tc.want, FewestPoolsScorer{})
+			require.NotEmpty(t, results)
+
+			count := 0
+			for _, a := range results[0].Allocations() {
+				count += a.DeviceCount
+			}
+			require.Equal(t, tc.expectCount, count)
+			require.Equal(t, tc.expectScore, results[0].Score)
+		})
+	}
+}
+
+func BenchmarkPoolSetAllocatorMaximum(b *testing.B) {
+	claim := api.DeviceClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foozer-claim",
+			Namespace: "default",
+		},
+	}
+
+	for _, count := range []int{12, 16, 20} {
+		pools := distinctPools(count)
+		b.Run(fmt.Sprintf("pools=%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				PoolSetAllocator{}.Allocate(claim, pools, 1, count, FewestPoolsScorer{})
+			}
+		})
+	}
+}
+
+func BenchmarkSelectNode(b *testing.B) {
+	claims := []api.DeviceClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foozer-claim",
+				Namespace: "default",
+			},
+			Spec: api.DeviceClaimSpec{
+				DeviceClass:    "example.com-foozer",
+				MinDeviceCount: ptr(6),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "barzer-claim",
+				Namespace: "default",
+			},
+			Spec: api.DeviceClaimSpec{
+				DeviceClass:    "gpu",
+				Driver:         ptr("example.com-barzer"),
+				MinDeviceCount: ptr(3),
+			},
+		},
+	}
+
+	for _, nodes := range []int{10, 100, 1000} {
+		pools := gen.GenFoozerBarzerNodes(nodes)
+		b.Run(fmt.Sprintf("nodes=%d", nodes), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				SelectNode(claims, testClasses(), testDrivers(), pools)
+			}
+		})
+	}
+}
+
+func BenchmarkPoolSetAllocatorManyPools(b *testing.B) {
+	claim := api.DeviceClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foozer-claim",
+			Namespace: "default",
+		},
+	}
+
+	for _, nodes := range []int{2, 4, 8} {
+		// four distinct kinds of foozer pool, with many of each
+		var pools []api.DevicePool
+		for _, p := range gen.GenFoozerBarzerNodes(nodes) {
+			if p.Spec.Driver == "example.com-foozer" {
+				pools = append(pools, p)
+			}
+		}
+		pools = sameNode(pools, "big-node")
+
+		for _, want := range []int{4, len(pools)} {
+			b.Run(fmt.Sprintf("pools=%d/devices=%d", len(pools), want), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					PoolSetAllocator{}.Allocate(claim, pools, want, want, FewestPoolsScorer{})
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/cel"
+)
+
+// AllocatePrivileged satisfies a DevicePrivilegedClaim on the given node. A
+// privileged claim selects every device on the node that is published by the
+// claim driver and meets the claim constraints, regardless of any ordinary
+// claims to those devices.
+//
+// Unlike SelectNode, the pools passed in should have their published device
+// counts, rather than the available devices. Privileged allocations do not
+// reduce the availability of the pools, so they should not be applied to the
+// pools when scheduling ordinary claims.
+//
+// The first returned value is the status for the claim, or nil if there are no
+// matching devices on the node. The second returned value is the result of
+// evaluating the node, which is marked as privileged.
+func AllocatePrivileged(claim api.DevicePrivilegedClaim, node string, pools []api.DevicePool) (*api.DevicePrivilegedClaimStatus, DeviceClaimResult) {
+	dcr := DeviceClaimResult{
+		ClaimName:  claim.Name,
+		Best:       -1,
+		Privileged: true,
+	}
+
+	psr := PoolSetResult{}
+	for _, p := range pools {
+		if p.Spec.NodeName == nil || *p.Spec.NodeName != node {
+			continue
+		}
+
+		if p.Spec.Driver != claim.Spec.Driver {
+			dcr.IgnoredPools = append(dcr.IgnoredPools, PoolResult{
+				PoolName:      p.Name,
+				FailureReason: "pool driver does not match the claim driver",
+			})
+			continue
+		}
+
+		if p.Spec.DeviceCount <= 0 {
+			dcr.IgnoredPools = append(dcr.IgnoredPools, PoolResult{
+				PoolName:      p.Name,
+				FailureReason: "no devices",
+			})
+			continue
+		}
+
+		meets, err := cel.MeetsConstraints(claim.Spec.Constraints, p.Spec.Attributes)
+		if err != nil {
+			dcr.IgnoredPools = append(dcr.IgnoredPools, PoolResult{
+				PoolName:      p.Name,
+				FailureReason: fmt.Sprintf("error evaluating claim constraints: %s", err.Error()),
+			})
+			continue
+		}
+		if !meets {
+			dcr.IgnoredPools = append(dcr.IgnoredPools, PoolResult{
+				PoolName:      p.Name,
+				FailureReason: "claim constraints not met",
+			})
+			continue
+		}
+
+		psr.PoolResults = append(psr.PoolResults, PoolResult{
+			PoolName:    p.Name,
+			DeviceCount: p.Spec.DeviceCount,
+		})
+	}
+
+	if len(psr.PoolResults) == 0 {
+		dcr.FailureReason = fmt.Sprintf("no devices from driver %q on node %q match the claim", claim.Spec.Driver, node)
+		return nil, dcr
+	}
+
+	psr.Score = 100
+	dcr.PoolSetResults = []PoolSetResult{psr}
+	dcr.Best = 0
+
+	return &api.DevicePrivilegedClaimStatus{
+		Allocations: dcr.Allocations(),
+	}, dcr
+}
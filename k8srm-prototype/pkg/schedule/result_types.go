@@ -10,6 +10,13 @@ import (
 type NodeResult struct {
 	NodeName           string
 	DeviceClaimResults []DeviceClaimResult
+
+	// Solver indicates which solver produced the results.
+	Solver SolverMode
+
+	// NodeScore is the score given to the node by the Scorer, if all the
+	// claims were satisfied.
+	NodeScore int
 }
 
 // DeviceClaimResult contains the results of an attempt to satisfy a
@@ -20,6 +27,13 @@ type DeviceClaimResult struct {
 	Best           int             `json:"best"`
 
 	IgnoredPools []PoolResult `json:"ignoredPools,omitempty"`
+
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// Privileged is set for the results of a DevicePrivilegedClaim. These
+	// allocations do not consume the devices, and may coexist with the
+	// allocations of ordinary claims.
+	Privileged bool `json:"privileged,omitempty"`
 }
 
 // PoolSetResult contains the results of an attempt to satisfy a
@@ -44,27 +58,40 @@ type PoolResult struct {
 
 func (nr *NodeResult) Score() int {
 	// The score for this node is zero if any
-	// claim could not be satisfied, and the average
-	// score for all claims otherwise.
-	sum := 0
+	// claim could not be satisfied, and the score
+	// given by the Scorer otherwise.
+	if !nr.satisfied() {
+		return 0
+	}
+
+	return nr.NodeScore
+}
+
+func (nr *NodeResult) satisfied() bool {
 	for _, dcr := range nr.DeviceClaimResults {
 		if dcr.Score() == 0 {
-			return 0
+			return false
 		}
-
-		sum += dcr.Score()
 	}
 
-	return sum / len(nr.DeviceClaimResults)
+	return true
 }
 
+// Allocations returns the allocations for all the claims, or nil if any claim
+// was not satisfied. The allocations of privileged claims are left out, since
+// they do not consume the devices. It may be called on the nil result that
+// SelectNode returns when no node was selected.
 func (nr *NodeResult) Allocations() []api.DevicePoolAllocation {
-	if nr.Score() == 0 {
+	if nr == nil || !nr.satisfied() {
 		return nil
 	}
 
 	var allocations []api.DevicePoolAllocation
 	for _, dcr := range nr.DeviceClaimResults {
+		if dcr.Privileged {
+			continue
+		}
+
 		allocations = append(allocations, dcr.Allocations()...)
 	}
 
@@ -72,6 +99,10 @@ func (nr *NodeResult) Allocations() []api.DevicePoolAllocation {
 }
 
 func (nr *NodeResult) Summary() string {
+	if nr.Score() > 0 && nr.Solver == SolverExhaustive {
+		return fmt.Sprintf("%s: satisfied all claims with score %d using exhaustive search", nr.NodeName, nr.Score())
+	}
+
 	if nr.Score() > 0 {
 		return fmt.Sprintf("%s: satisfied all claims with score %d", nr.NodeName, nr.Score())
 	}
@@ -2,10 +2,10 @@ package schedule
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
-
-	"gonum.org/v1/gonum/stat/combin"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/cel"
 )
 
 // SelectNode will select the node that can best satisfy all the claims.
@@ -15,52 +15,74 @@ import (
 // be reduced to only the available devices. The algorithm here will consider
 // allocations made only by claims passed to this function.
 //
-// The first returned value is an array of the allocations from each pool that
-// are needed to satisfy all the claims. In the event no node can be selected,
-// this will be empty. The second returned value is an array of the results of
-// evaluating each node.
-
-func SelectNode(claims []api.DeviceClaim, pools []api.DevicePool) ([]api.DevicePoolAllocation, []NodeResult) {
-	// Collect the pools by node
-	poolsByNode := make(map[string][]api.DevicePool)
-	for _, p := range pools {
-		// ignore pools not associated with a node
-		if p.Spec.NodeName == nil || *p.Spec.NodeName == "" {
-			continue
-		}
-
-		poolsByNode[*p.Spec.NodeName] = append(poolsByNode[*p.Spec.NodeName], p)
-	}
+// The classes and drivers are used to look up the DeviceClass of each claim,
+// and to find the drivers that may satisfy it. A claim whose class is not
+// found cannot be satisfied.
+//
+// Pools with no NodeName are network attached, and are considered along with
+// the local pools of each node from which they can be reached. See
+// WithNodeLabels for evaluating pools with a NodeSelector.
+//
+// The behavior of the algorithm may be adjusted with the options; for
+// example, WithSolver selects how multiple claims are satisfied on a node, and
+// WithScorer selects how the candidate solutions are compared.
+//
+// The first returned value is the result for the selected node, whose
+// Allocations are the allocations from each pool that are needed to satisfy
+// all the claims. In the event no node can be selected, this will be nil. The
+// second returned value is an array of the results of evaluating each node,
+// which the first points into. Since network attached pools are shared, nodes
+// with different scores may have the same allocations, so callers must use the
+// selected result rather than search for one with matching allocations.
+func SelectNode(claims []api.DeviceClaim, classes []api.DeviceClass, drivers []api.DeviceDriver, pools []api.DevicePool, opts ...Option) (*NodeResult, []NodeResult) {
+	o := buildOptions(opts)
+
+	classesByName := classesByName(classes)
 
 	var results []NodeResult
-	i := -1
-	best := -1
 	// Evaluate each node against the claims
-	for node, nodeDevPools := range poolsByNode {
-		nr := evaluateNode(node, claims, nodeDevPools)
-		results = append(results, nr)
-		i += 1
+	for node, nodeDevPools := range poolsByNode(pools, o.nodeLabels) {
+		results = append(results, evaluateNode(node, claims, classesByName, drivers, nodeDevPools, o))
+	}
 
-		if best > -1 && nr.Score() > results[best].Score() {
-			best = i
+	best := bestNode(results)
+	if best == -1 {
+		return nil, results
+	}
+
+	return &results[best], results
+}
+
+func classesByName(classes []api.DeviceClass) map[string]*api.DeviceClass {
+	result := make(map[string]*api.DeviceClass)
+	for i := range classes {
+		result[classes[i].Name] = &classes[i]
+	}
+
+	return result
+}
+
+// bestNode returns the index of the first node result with the highest score,
+// or -1 if no node satisfied all the claims.
+func bestNode(results []NodeResult) int {
+	best := -1
+	for i := range results {
+		if results[i].Score() == 0 {
 			continue
 		}
 
-		if best == -1 && nr.Score() > 0 {
+		if best == -1 || results[i].Score() > results[best].Score() {
 			best = i
 		}
 	}
 
-	if best == -1 {
-		return nil, results
-	}
-
-	return results[best].Allocations(), results
+	return best
 }
 
-func evaluateNode(node string, claims []api.DeviceClaim, pools []api.DevicePool) NodeResult {
+func evaluateNode(node string, claims []api.DeviceClaim, classes map[string]*api.DeviceClass, drivers []api.DeviceDriver, pools []api.DevicePool, o *options) NodeResult {
 	nr := NodeResult{
 		NodeName: node,
+		Solver:   SolverGreedy,
 	}
 
 	// Theoretically, when there are multiple claims, the order in which
@@ -70,51 +92,80 @@ func evaluateNode(node string, claims []api.DeviceClaim, pools []api.DevicePool)
 	// with multiple claims for the similar devices, could conceivably
 	// result in one order being solvable, and another not being solvable.
 	//
-	// Regardless, for the prototype we will not worry about this, and will
-	// just evaluate the claims in the order presented.
+	// So, we first just evaluate the claims in the order presented. If that
+	// fails, and the exhaustive solver is enabled, we search over the other
+	// orderings and pool set choices, within the configured budget.
+	//
+	// We work on a copy of the pools, so that we can apply the allocations
+	// of each claim before evaluating the next one. Otherwise, subsequent,
+	// overlapping claims could double-allocate the same devices.
+	available := make([]api.DevicePool, len(pools))
+	copy(available, pools)
+
 	for _, c := range claims {
-		dcr := evaluateNodeForClaim(c, pools)
+		dcr := evaluateNodeForClaim(c, classes[c.Spec.DeviceClass], drivers, available, o)
 		nr.DeviceClaimResults = append(nr.DeviceClaimResults, dcr)
 
-		// TODO: apply the allocations to the underlying pools, so that
-		// subsequent, overlapping claims do not double-allocate
+		applyAllocations(available, dcr.Allocations())
+	}
+
+	if o.solver == SolverExhaustive && !nr.satisfied() {
+		if dcrs := searchNode(claims, classes, drivers, pools, o); dcrs != nil {
+			nr.DeviceClaimResults = dcrs
+			nr.Solver = SolverExhaustive
+		}
+	}
+
+	if nr.satisfied() {
+		nr.NodeScore = clampScore(o.scorer.ScoreNode(&nr, pools))
 	}
 
 	return nr
 }
 
-func evaluateNodeForClaim(claim api.DeviceClaim, pools []api.DevicePool) DeviceClaimResult {
+// applyAllocations reduces the device counts of the pools by the allocated
+// devices.
+func applyAllocations(pools []api.DevicePool, allocations []api.DevicePoolAllocation) {
+	for _, a := range allocations {
+		for i := range pools {
+			if pools[i].Name == a.DevicePoolName {
+				pools[i].Spec.DeviceCount -= a.DeviceCount
+			}
+		}
+	}
+}
+
+func evaluateNodeForClaim(claim api.DeviceClaim, class *api.DeviceClass, drivers []api.DeviceDriver, pools []api.DevicePool, o *options) DeviceClaimResult {
 	dcr := DeviceClaimResult{
 		ClaimName: claim.Name,
 		Best:      -1,
 	}
 
-	// This function implements an algorithm which assumes that allocating
-	// multiple devices out of the same pool is better than allocating them
-	// out of different pools. This allows device drivers to organize their
-	// pools by some coarse topology like NUMA. We may want to break this
-	// out into an interface and experiment with a couple algorithms. For
-	// example, we could "flatten" all pools into just a set of devices,
-	// and treat Node as a MatchAttributes field.
+	// Resolve the claim against its class, so the rest of the evaluation
+	// only needs to look at the claim.
+	effective, err := api.EffectiveClaim(&claim, class)
+	if err != nil {
+		dcr.FailureReason = err.Error()
+		return dcr
+	}
 
-	// Pool-Based Algorithm Assumptions
-	//
-	// A brute force approach would enumerate every permutation of 1 or
-	// more pools, and then score the claim against each. We can do some
-	// early pruning by following these principles:
-	// - Any pools that do not match the constraints can be
-	//   removed from consideration, reducing the combinatorial effect.
-	// - Any pools with no available devices can similarly be removed
-	//   (recall that the passed in pools have their availability reduced
-	//   according to any existing allocations).
-	// - Ordering of the pools does not matter when evaluating single claim
-	//   against a list of pools. Thus we can evaluate combinations (sets)
-	//   rather than permutations of pools.
-	// - A solution with fewer pools is always better. This means we can
-	//   start with sets of one pool, only proceeding to two pool
-	//   combinations if no single pool works, and so on.
+	allowedDrivers, err := claimDrivers(effective, class, drivers)
+	if err != nil {
+		dcr.FailureReason = err.Error()
+		return dcr
+	}
 
-	// First, eliminate any non-matching or fully committed pools.
+	// When the drivers publish attribute schemas, mistakes in the
+	// constraints are found once here, rather than for every pool.
+	if err := checkConstraints("constraints", effective.Spec.Constraints, allowedDrivers, drivers); err != nil {
+		dcr.FailureReason = err.Error()
+		return dcr
+	}
+
+	minCount, maxCount := *effective.Spec.MinDeviceCount, effective.Spec.MaxDeviceCount
+
+	// First, eliminate any non-matching or fully committed pools. This
+	// reduces the work for the allocator.
 	var goodPools []api.DevicePool
 	for _, p := range pools {
 		if p.Spec.DeviceCount <= 0 {
@@ -125,16 +176,15 @@ func evaluateNodeForClaim(claim api.DeviceClaim, pools []api.DevicePool) DeviceC
 			continue
 		}
 
-		if claim.Spec.Driver != nil && *claim.Spec.Driver != p.Spec.Driver {
+		if !allowedDrivers[p.Spec.Driver] {
 			dcr.IgnoredPools = append(dcr.IgnoredPools, PoolResult{
 				PoolName:      p.Name,
-				FailureReason: "claim and pool driver do not match",
+				FailureReason: "pool driver cannot satisfy the claim",
 			})
 			continue
 		}
 
-		// TODO: Consider *class* contraints
-		meets, err := MeetsConstraints(claim.Spec.Constraints, p.Spec.Attributes)
+		meets, err := cel.MeetsConstraints(effective.Spec.Constraints, p.Spec.Attributes)
 		if err != nil {
 			dcr.IgnoredPools = append(dcr.IgnoredPools, PoolResult{
 				PoolName:      p.Name,
@@ -153,40 +203,187 @@ func evaluateNodeForClaim(claim api.DeviceClaim, pools []api.DevicePool) DeviceC
 		goodPools = append(goodPools, p)
 	}
 
-	// Now, iterate through the possible lengths of different combination sets,
-	// scoring each set. If we find one or more successful sets at a given size,
-	// we do not need to continue evaluating the next size, based on the principle
-	// stated above.
-	for setSize := 1; setSize <= len(goodPools); setSize++ {
-		combinations := combin.Combinations(len(goodPools), setSize)
-		for _, combo := range combinations {
-			psr := evaluatePoolSetForClaim(claim, poolSet(goodPools, combo))
-			dcr.PoolSetResults = append(dcr.PoolSetResults, psr)
+	// Figure out how many devices we want. Normally, this is just the
+	// minimum. If we are allocating as many as possible, it is the maximum,
+	// or all the devices on the node if there is no maximum.
+	want := minCount
+	if o.allocationPolicy == AllocationPolicyMaximum {
+		if maxCount != nil {
+			want = *maxCount
+		} else {
+			want = 0
+			for _, p := range goodPools {
+				want += p.Spec.DeviceCount
+			}
+		}
+	}
+
+	// Finally, the allocator selects devices from the remaining pools.
+	dcr.PoolSetResults = o.allocator.Allocate(*effective, goodPools, minCount, want, o.scorer)
+	for i, psr := range dcr.PoolSetResults {
+		if psr.Score > 0 {
+			if dcr.Best == -1 || psr.Score > dcr.PoolSetResults[dcr.Best].Score {
+				dcr.Best = i
+			}
+		}
+	}
+
+	return dcr
+}
+
+// PoolSetAllocator is the default Allocator. It allocates devices from sets of
+// whole pools.
+type PoolSetAllocator struct {
+	// MaxResults limits the number of pool sets returned, keeping only
+	// those with the best scores. Default is DefaultMaxPoolSetResults. The
+	// exhaustive solver raises it to the search budget.
+	MaxResults int
+}
+
+var _ Allocator = PoolSetAllocator{}
+
+// DefaultMaxPoolSetResults is the default number of pool sets returned by the
+// PoolSetAllocator.
+const DefaultMaxPoolSetResults = 10
+
+func (a PoolSetAllocator) maxResults() int {
+	if a.MaxResults <= 0 {
+		return DefaultMaxPoolSetResults
+	}
+
+	return a.MaxResults
+}
+
+func (a PoolSetAllocator) Allocate(claim api.DeviceClaim, pools []api.DevicePool, minCount, want int, scorer Scorer) []PoolSetResult {
+	// This function implements an algorithm which assumes that allocating
+	// multiple devices out of the same pool is better than allocating them
+	// out of different pools. This allows device drivers to organize their
+	// pools by some coarse topology like NUMA. See FlattenedAllocator for
+	// an alternative that treats the pools as just a set of devices.
+
+	// Pool-Based Algorithm Assumptions
+	//
+	// A brute force approach would enumerate every permutation of 1 or
+	// more pools, and then score the claim against each. We can do some
+	// early pruning by following these principles:
+	// - Any pools that do not match the constraints can be
+	//   removed from consideration, reducing the combinatorial effect.
+	// - Any pools with no available devices can similarly be removed
+	//   (recall that the passed in pools have their availability reduced
+	//   according to any existing allocations). These first two are
+	//   done in evaluateNodeForClaim, before calling the allocator.
+	// - Ordering of the pools does not matter when evaluating single claim
+	//   against a list of pools. Thus we can evaluate combinations (sets)
+	//   rather than permutations of pools.
+	// - A solution with fewer pools is always better. This means we can
+	//   start with sets of one pool, only proceeding to two pool
+	//   combinations if no single pool works, and so on. When allocating
+	//   as many devices as possible, a larger set is only better if it
+	//   gets us more of the requested range.
+	// - Pools with the same driver, attributes, and available devices are
+	//   interchangeable. Sets that differ only by swapping such pools will
+	//   get the same result, so we only need to evaluate one of them.
+	// - If the largest pools cannot provide the minimum devices with a
+	//   given set size, no set of that size can, so we can skip it.
+	// - No set can allocate more than all the pools together, so we are
+	//   done once a set allocates that many, even if it is less than want.
+	//   Smaller sets cannot get that far if their largest pools cannot, so
+	//   we start with the smallest size whose largest pools can.
+	// - If that fails, for example because of MatchAttributes, smaller
+	//   sets are only worth trying if their largest pools could allocate
+	//   as many devices as the best set found so far.
+	//
+	// Even so, there may be a very large number of sets, so rather than
+	// materializing them all, we enumerate them one at a time and keep only
+	// the best results.
+	groups := equivalentPools(pools)
+	capacities := capacitySums(pools)
+	results := newTopPoolSetResults(a.maxResults())
+	if len(pools) == 0 {
+		return results.list()
+	}
+
+	target := want
+	if capacity := capacities[len(capacities)-1]; capacity < target {
+		target = capacity
+	}
+
+	first := sort.SearchInts(capacities, target) + 1
+	best := 0
+
+	// evaluateSetSize scores each set of the size, and returns true if
+	// one of them allocates the target.
+	evaluateSetSize := func(setSize int) bool {
+		complete := false
+		forEachPoolSet(groups, setSize, func(set []api.DevicePool) {
+			psr := evaluatePoolSetForClaim(claim, set, minCount, want)
 			if psr.Score > 0 {
-				if dcr.Best == -1 || psr.Score > dcr.PoolSetResults[dcr.Best].Score {
-					dcr.Best = len(dcr.PoolSetResults) - 1
+				allocated := 0
+				for _, pr := range psr.PoolResults {
+					allocated += pr.DeviceCount
 				}
-
+				best = max(best, allocated)
+				complete = complete || allocated >= target
+				psr.Score = clampScore(scorer.ScorePoolSet(claim, set, psr))
 			}
+
+			results.add(psr)
+		})
+
+		return complete
+	}
+
+	// Iterate through the set sizes that may allocate the target, scoring
+	// each set. If we find one or more sets at a given size that do, we
+	// do not need to continue evaluating the next size, based on the
+	// principle stated above. Successful sets are then scored by the
+	// Scorer.
+	for setSize := first; setSize <= len(pools); setSize++ {
+		if capacities[setSize-1] < minCount {
+			continue
 		}
-		// if we found at least one that works, we do not need to check
-		// the next setSize, and we are done
-		if dcr.Best > -1 {
-			return dcr
+
+		if evaluateSetSize(setSize) {
+			return results.list()
 		}
 	}
 
-	return dcr
+	// Otherwise, try the smaller sets that may do as well as the best set
+	// so far with fewer pools. The capacities only shrink from here.
+	for setSize := first - 1; setSize >= 1; setSize-- {
+		if capacities[setSize-1] < minCount || capacities[setSize-1] < best {
+			break
+		}
+
+		evaluateSetSize(setSize)
+	}
+
+	return results.list()
 }
 
-func poolSet(pools []api.DevicePool, combo []int) []api.DevicePool {
-	var result []api.DevicePool
+// claimDrivers returns the set of drivers that may satisfy the effective
+// claim. If the claim names a driver, only that driver is allowed. Otherwise,
+// every driver registered for the class DeviceType is allowed.
+func claimDrivers(claim *api.DeviceClaim, class *api.DeviceClass, drivers []api.DeviceDriver) (map[string]bool, error) {
+	result := make(map[string]bool)
+	for _, d := range drivers {
+		if claim.Spec.Driver != nil && *claim.Spec.Driver != d.Name {
+			continue
+		}
+
+		for _, dt := range d.DeviceTypes {
+			if dt == class.Spec.DeviceType {
+				result[d.Name] = true
+				break
+			}
+		}
+	}
 
-	for _, index := range combo {
-		result = append(result, pools[index])
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no registered driver can satisfy device class %q", class.Name)
 	}
 
-	return result
+	return result, nil
 }
 
 // attempts to satisfy the claim using the specified pools
@@ -196,17 +393,15 @@ func poolSet(pools []api.DevicePool, combo []int) []api.DevicePool {
 //   - No subset will satisfy the claim. That is, we must use ALL the
 //     passed pools. This is important otherwise we need to consider
 //     MatchAttributes across permutations, not combinations.
-func evaluatePoolSetForClaim(claim api.DeviceClaim, pools []api.DevicePool) PoolSetResult {
-	required := 1
-	if claim.Spec.MinDeviceCount != nil {
-		required = *claim.Spec.MinDeviceCount
-	}
-
-	origRequired := required
+//
+// It will allocate up to want devices, and succeeds if at least minCount
+// devices are allocated. The score is the percentage of want that was
+// allocated.
+func evaluatePoolSetForClaim(claim api.DeviceClaim, pools []api.DevicePool, minCount, want int) PoolSetResult {
+	required := want
 
 	psr := PoolSetResult{}
 
-	// TODO: Include both claim and class MatchAttributes
 	matchAttrs := make(map[string]api.Attribute)
 
 	for i, p := range pools {
@@ -244,11 +439,19 @@ func evaluatePoolSetForClaim(claim api.DeviceClaim, pools []api.DevicePool) Pool
 		psr.PoolResults = append(psr.PoolResults, pr)
 	}
 
-	if required > 0 {
+	setAllocationScore(&psr, want-required, minCount, want)
+	return psr
+}
+
+// setAllocationScore sets the score of the pool set to the percentage of want
+// that was allocated, or fails it if less than minCount was allocated.
+func setAllocationScore(psr *PoolSetResult, allocated, minCount, want int) {
+	if allocated < minCount {
 		psr.Score = 0
-		psr.FailureReason = fmt.Sprintf("unable to satisfy %d of %d device requests", required, origRequired)
-	} else {
-		psr.Score = 100
+		psr.FailureReason = fmt.Sprintf("unable to satisfy %d of %d device requests", minCount-allocated, minCount)
+		return
 	}
-	return psr
+
+	// a zero score means failure, so round up any success
+	psr.Score = clampScore(100 * allocated / want)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/gen"
 	"github.com/stretchr/testify/require"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/yaml"
@@ -35,6 +36,84 @@ func dumpTestClaims(tn string, claims []api.DeviceClaim) {
 	}
 }
 
+func testDrivers() []api.DeviceDriver {
+	return []api.DeviceDriver{
+		{
+			ObjectMeta:  metav1.ObjectMeta{Name: "example.com-foozer"},
+			DeviceTypes: []string{"gpu"},
+		},
+		{
+			ObjectMeta:  metav1.ObjectMeta{Name: "example.com-barzer"},
+			DeviceTypes: []string{"gpu", "sriov-nic"},
+		},
+		{
+			ObjectMeta:  metav1.ObjectMeta{Name: "sriov-nic"},
+			DeviceTypes: []string{"sriov-nic"},
+		},
+	}
+}
+
+func testClasses() []api.DeviceClass {
+	return []api.DeviceClass{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+			Spec: api.DeviceClassSpec{
+				DeviceType: "gpu",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "example.com-foozer"},
+			Spec: api.DeviceClassSpec{
+				DeviceType: "gpu",
+				Driver:     ptr("example.com-foozer"),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foozer-up-to-four"},
+			Spec: api.DeviceClassSpec{
+				DeviceType:     "gpu",
+				Driver:         ptr("example.com-foozer"),
+				MaxDeviceCount: ptr(4),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foozer-at-least-two"},
+			Spec: api.DeviceClassSpec{
+				DeviceType:     "gpu",
+				Driver:         ptr("example.com-foozer"),
+				MinDeviceCount: ptr(2),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foozer-same-numa"},
+			Spec: api.DeviceClassSpec{
+				DeviceType:      "gpu",
+				Driver:          ptr("example.com-foozer"),
+				MatchAttributes: []string{"numa"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foozer-1000"},
+			Spec: api.DeviceClassSpec{
+				DeviceType:  "gpu",
+				Constraints: ptr("device.model == 'foozer-1000'"),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sriov-nic"},
+			Spec: api.DeviceClassSpec{
+				DeviceType: "sriov-nic",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vlan"},
+			Spec: api.DeviceClassSpec{
+				DeviceType: "vlan",
+			},
+		},
+	}
+}
+
 func TestSelectNode(t *testing.T) {
 	mixedPools := append(gen.GenShapeZero(2), gen.GenShapeThree(2)...)
 	testCases := map[string]struct {
@@ -42,6 +121,113 @@ func TestSelectNode(t *testing.T) {
 		pools         []api.DevicePool
 		expectSuccess bool
 	}{
+		"class not found": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "no-such-class",
+					},
+				},
+			},
+			pools:         mixedPools,
+			expectSuccess: false,
+		},
+		"class driver enforced": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "example.com-foozer",
+					},
+				},
+			},
+			pools:         gen.GenShapeThree(2),
+			expectSuccess: false,
+		},
+		"claim driver does not match class driver": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "example.com-foozer",
+						Driver:      ptr("example.com-barzer"),
+					},
+				},
+			},
+			pools:         mixedPools,
+			expectSuccess: false,
+		},
+		"device type expands to registered drivers": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "sriov-nic",
+					},
+				},
+			},
+			pools:         mixedPools,
+			expectSuccess: true,
+		},
+		"no driver registered for device type": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "vlan",
+					},
+				},
+			},
+			pools:         mixedPools,
+			expectSuccess: false,
+		},
+		"class constraint not met": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "foozer-1000",
+					},
+				},
+			},
+			pools:         gen.GenShapeTwo(2),
+			expectSuccess: false,
+		},
+		"class and claim constraints are ANDed": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "foozer-1000",
+						Constraints: ptr("device.model == 'barzer-1000'"),
+					},
+				},
+			},
+			pools:         mixedPools,
+			expectSuccess: false,
+		},
 		"single by driver": {
 			claims: []api.DeviceClaim{
 				{
@@ -50,7 +236,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass: "not implemented yet",
+						DeviceClass: "gpu",
 						Driver:      ptr("example.com-foozer"),
 					},
 				},
@@ -66,7 +252,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass: "not implemented yet",
+						DeviceClass: "gpu",
 						Constraints: ptr("device.model == 'foozer-1000'"),
 					},
 				},
@@ -82,7 +268,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass: "not implemented yet",
+						DeviceClass: "gpu",
 						Constraints: ptr("device.model == 'foozer-8000'"),
 					},
 				},
@@ -98,7 +284,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass:    "not implemented yet",
+						DeviceClass:    "gpu",
 						Driver:         ptr("example.com-foozer"),
 						MinDeviceCount: ptr(2),
 					},
@@ -115,7 +301,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass:    "not implemented yet",
+						DeviceClass:    "gpu",
 						Driver:         ptr("example.com-foozer"),
 						MinDeviceCount: ptr(4),
 					},
@@ -132,7 +318,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass:    "not implemented yet",
+						DeviceClass:    "gpu",
 						Driver:         ptr("example.com-foozer"),
 						MinDeviceCount: ptr(2),
 					},
@@ -143,7 +329,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass:    "not implemented yet",
+						DeviceClass:    "gpu",
 						Driver:         ptr("example.com-barzer"),
 						MinDeviceCount: ptr(2),
 					},
@@ -160,7 +346,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass:    "not implemented yet",
+						DeviceClass:    "gpu",
 						Driver:         ptr("example.com-foozer"),
 						MinDeviceCount: ptr(2),
 					},
@@ -171,7 +357,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass:    "not implemented yet",
+						DeviceClass:    "gpu",
 						Driver:         ptr("example.com-barzer"),
 						MinDeviceCount: ptr(2),
 					},
@@ -180,6 +366,168 @@ func TestSelectNode(t *testing.T) {
 			pools:         gen.GenFoozerBarzerNodes(2),
 			expectSuccess: true,
 		},
+		"two overlapping claims fit in one pool": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "claim-one",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "example.com-foozer",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "claim-two",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "example.com-foozer",
+					},
+				},
+			},
+			pools:         gen.GenShapeZero(2),
+			expectSuccess: true,
+		},
+		"two overlapping claims do not fit in one pool": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "claim-one",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass:    "example.com-foozer",
+						MinDeviceCount: ptr(2),
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "claim-two",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "example.com-foozer",
+					},
+				},
+			},
+			pools:         gen.GenShapeZero(2),
+			expectSuccess: false,
+		},
+		"three overlapping claims do not fit in one pool": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "claim-one",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "example.com-foozer",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "claim-two",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "example.com-foozer",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "claim-three",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "example.com-foozer",
+					},
+				},
+			},
+			pools:         gen.GenShapeZero(2),
+			expectSuccess: false,
+		},
+		"class minimum applies to claim": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass: "foozer-at-least-two",
+					},
+				},
+			},
+			pools:         gen.GenShapeZero(2),
+			expectSuccess: true,
+		},
+		"claim minimum less than class minimum": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass:    "foozer-at-least-two",
+						MinDeviceCount: ptr(1),
+					},
+				},
+			},
+			pools:         gen.GenShapeZero(2),
+			expectSuccess: false,
+		},
+		"claim maximum greater than class maximum": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass:    "foozer-up-to-four",
+						MaxDeviceCount: ptr(8),
+					},
+				},
+			},
+			pools:         gen.GenShapeZero(2),
+			expectSuccess: false,
+		},
+		"claim minimum greater than class maximum": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass:    "foozer-up-to-four",
+						MinDeviceCount: ptr(5),
+					},
+				},
+			},
+			pools:         gen.GenShapeTwo(2),
+			expectSuccess: false,
+		},
+		"claim minimum greater than claim maximum": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass:    "gpu",
+						MinDeviceCount: ptr(2),
+						MaxDeviceCount: ptr(1),
+					},
+				},
+			},
+			pools:         gen.GenShapeZero(2),
+			expectSuccess: false,
+		},
 		"claim cannot be met due to NUMA MatchAttribute": {
 			claims: []api.DeviceClaim{
 				{
@@ -188,7 +536,7 @@ func TestSelectNode(t *testing.T) {
 						Namespace: "default",
 					},
 					Spec: api.DeviceClaimSpec{
-						DeviceClass:     "not implemented yet",
+						DeviceClass:     "gpu",
 						Driver:          ptr("example.com-foozer"),
 						MinDeviceCount:  ptr(4),
 						MatchAttributes: []string{"numa"},
@@ -198,36 +546,846 @@ func TestSelectNode(t *testing.T) {
 			pools:         gen.GenShapeOne(2),
 			expectSuccess: false,
 		},
-	}
-
-	for tn, tc := range testCases {
-		verbose := os.Getenv("VERBOSE") == "y"
-
-		t.Run(tn, func(t *testing.T) {
-			dumpTestClaims(tn, tc.claims)
-			allocations, results := SelectNode(tc.claims, tc.pools)
-			b, _ := yaml.Marshal(allocations)
-			fmt.Println()
-			fmt.Println("=== TEST " + tn)
-			fmt.Println()
-			fmt.Println("ALLOCATIONS")
-			fmt.Println("-----------")
-			fmt.Println(string(b))
-			fmt.Println("NODE RESULTS")
-			fmt.Println("------------")
-			if verbose {
-				b, _ = yaml.Marshal(results)
-				fmt.Println(string(b))
-			} else {
-				for _, nr := range results {
-					fmt.Println(nr.Summary())
-				}
-			}
-			fmt.Println()
-			fmt.Println("=== DONE " + tn)
-			fmt.Println()
-
+		"claim cannot be met due to class NUMA MatchAttribute": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass:    "foozer-same-numa",
+						MinDeviceCount: ptr(4),
+					},
+				},
+			},
+			pools:         gen.GenShapeOne(2),
+			expectSuccess: false,
+		},
+		"match attributes compare typed values": {
+			claims: []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass:     "example.com-foozer",
+						MinDeviceCount:  ptr(3),
+						MatchAttributes: []string{"memory", "zone"},
+					},
+				},
+			},
+			// The first two pools match, though their quantities
+			// are written differently. The third does not, as its
+			// zone is a string.
+			pools: []api.DevicePool{
+				typedAttributesPool("typed-00", 2, "1Gi", api.Attribute{Name: "zone", IntValue: ptr(1)}),
+				typedAttributesPool("typed-01", 1, "1073741824", api.Attribute{Name: "zone", IntValue: ptr(1)}),
+				typedAttributesPool("typed-02", 2, "1Gi", api.Attribute{Name: "zone", StringValue: ptr("1")}),
+			},
+			expectSuccess: true,
+		},
+	}
+
+	// Every case is run against each allocator, and the outcomes compared.
+	allocators := []struct {
+		name      string
+		allocator Allocator
+	}{
+		{"pool set", PoolSetAllocator{}},
+		{"flattened", FlattenedAllocator{}},
+	}
+
+	for tn, tc := range testCases {
+		verbose := os.Getenv("VERBOSE") == "y"
+
+		t.Run(tn, func(t *testing.T) {
+			dumpTestClaims(tn, tc.claims)
+			// the best nodes, and the allocations of each claim on each node,
+			// keyed by allocator
+			bestNodes := make(map[string][]string)
+			nodeAllocations := make(map[string]map[string][][]api.DevicePoolAllocation)
+			for _, a := range allocators {
+				selected, results := SelectNode(tc.claims, testClasses(), testDrivers(), tc.pools, WithAllocator(a.allocator))
+				allocations := selected.Allocations()
+				b, _ := yaml.Marshal(allocations)
+				fmt.Println()
+				fmt.Println("=== TEST " + tn + " [" + a.name + "]")
+				fmt.Println()
+				fmt.Println("ALLOCATIONS")
+				fmt.Println("-----------")
+				fmt.Println(string(b))
+				fmt.Println("NODE RESULTS")
+				fmt.Println("------------")
+				if verbose {
+					b, _ = yaml.Marshal(results)
+					fmt.Println(string(b))
+				} else {
+					for _, nr := range results {
+						fmt.Println(nr.Summary())
+					}
+				}
+				fmt.Println()
+				fmt.Println("=== DONE " + tn + " [" + a.name + "]")
+				fmt.Println()
+
+				require.Equal(t, tc.expectSuccess, allocations != nil, "allocator %s", a.name)
+				requireAllocationsFit(t, allocations, tc.pools)
+
+				nodeAllocations[a.name] = make(map[string][][]api.DevicePoolAllocation)
+				for _, nr := range results {
+					if selected != nil && nr.Score() == selected.Score() {
+						bestNodes[a.name] = append(bestNodes[a.name], nr.NodeName)
+					}
+
+					for _, dcr := range nr.DeviceClaimResults {
+						nodeAllocations[a.name][nr.NodeName] = append(nodeAllocations[a.name][nr.NodeName], dcr.Allocations())
+					}
+				}
+			}
+
+			// Nodes with equal scores are selected in no particular
+			// order, so the allocators must agree on which nodes are
+			// best, and on the pools used for each claim on each node.
+			first, second := allocators[0].name, allocators[1].name
+			require.ElementsMatch(t, bestNodes[first], bestNodes[second], "allocators selected different nodes")
+			for node, claimAllocations := range nodeAllocations[first] {
+				require.Len(t, nodeAllocations[second][node], len(claimAllocations))
+				for i := range claimAllocations {
+					require.ElementsMatch(t, claimAllocations[i], nodeAllocations[second][node][i], "allocators allocated different devices for claim %d on node %s", i, node)
+				}
+			}
+		})
+	}
+}
+
+// typedAttributesPool returns a foozer pool on node-00 with the memory
+// quantity and zone attributes.
+func typedAttributesPool(name string, count int, memory string, zone api.Attribute) api.DevicePool {
+	return api.DevicePool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: api.DevicePoolSpec{
+			NodeName:    ptr("node-00"),
+			Driver:      "example.com-foozer",
+			DeviceCount: count,
+			Attributes: []api.Attribute{
+				{Name: "memory", QuantityValue: ptr(resource.MustParse(memory))},
+				zone,
+			},
+		},
+	}
+}
+
+// requireAllocationsFit checks that no pool has more devices allocated than it
+// contains, even when the allocations come from different claims.
+func requireAllocationsFit(t *testing.T, allocations []api.DevicePoolAllocation, pools []api.DevicePool) {
+	allocated := make(map[string]int)
+	for _, a := range allocations {
+		allocated[a.DevicePoolName] += a.DeviceCount
+	}
+
+	for _, p := range pools {
+		require.LessOrEqual(t, allocated[p.Name], p.Spec.DeviceCount, "pool %s is over-allocated", p.Name)
+	}
+}
+
+func TestSelectNodeSolver(t *testing.T) {
+	// The first claim will greedily take a device from the numa 0 pool,
+	// leaving too few for the second claim, which requires numa 0.
+	claims := []api.DeviceClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "any-numa",
+				Namespace: "default",
+			},
+			Spec: api.DeviceClaimSpec{
+				DeviceClass: "example.com-foozer",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "numa-zero",
+				Namespace: "default",
+			},
+			Spec: api.DeviceClaimSpec{
+				DeviceClass:    "example.com-foozer",
+				Constraints:    ptr("device.numa == '0'"),
+				MinDeviceCount: ptr(2),
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		opts          []Option
+		expectSuccess bool
+		expectSolver  SolverMode
+	}{
+		"greedy fails": {
+			expectSuccess: false,
+			expectSolver:  SolverGreedy,
+		},
+		"exhaustive succeeds": {
+			opts:          []Option{WithSolver(SolverExhaustive)},
+			expectSuccess: true,
+			expectSolver:  SolverExhaustive,
+		},
+		"exhaustive out of budget": {
+			opts:          []Option{WithSolver(SolverExhaustive), WithSearchBudget(1)},
+			expectSuccess: false,
+			expectSolver:  SolverGreedy,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			pools := gen.GenShapeOne(1)
+			selected, results := SelectNode(claims, testClasses(), testDrivers(), pools, tc.opts...)
+			allocations := selected.Allocations()
 			require.Equal(t, tc.expectSuccess, allocations != nil)
+			require.Len(t, results, 1)
+			require.Equal(t, tc.expectSolver, results[0].Solver)
+			requireAllocationsFit(t, allocations, pools)
+
+			if tc.expectSuccess {
+				require.Equal(t, []api.DevicePoolAllocation{
+					{DevicePoolName: "shape-one-00-foozer-00", DeviceCount: 2},
+				}, results[0].DeviceClaimResults[1].Allocations())
+			}
 		})
 	}
 }
+
+func TestSelectNodeSolverBeyondMaxPoolSetResults(t *testing.T) {
+	// Each claim needs eight devices, from the four shared pools and six
+	// pools of its own side. Taking more than two shared pools leaves too
+	// few for the other claim, but the pool sets that do so come first, and
+	// there are more than DefaultMaxPoolSetResults of them, so the only
+	// solution is found beyond the best pool sets of either claim.
+	var pools []api.DevicePool
+	for _, side := range []string{"shared", "left", "right"} {
+		count := 6
+		if side == "shared" {
+			count = 4
+		}
+
+		for i := 0; i < count; i++ {
+			pool := api.DevicePool{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%d", side, i)},
+				Spec: api.DevicePoolSpec{
+					NodeName:    ptr("node-00"),
+					Driver:      "example.com-foozer",
+					DeviceCount: 1,
+					Attributes: []api.Attribute{
+						{Name: "side", StringValue: ptr(side)},
+					},
+				},
+			}
+
+			// the shared pools are interchangeable, the others are not
+			if side != "shared" {
+				pool.Spec.Attributes = append(pool.Spec.Attributes, api.Attribute{Name: "index", IntValue: ptr(i)})
+			}
+			pools = append(pools, pool)
+		}
+	}
+
+	var claims []api.DeviceClaim
+	for _, side := range []string{"left", "right"} {
+		other := "right"
+		if side == "right" {
+			other = "left"
+		}
+
+		claims = append(claims, api.DeviceClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      side,
+				Namespace: "default",
+			},
+			Spec: api.DeviceClaimSpec{
+				DeviceClass:    "example.com-foozer",
+				Constraints:    ptr(fmt.Sprintf("device.side != '%s'", other)),
+				MinDeviceCount: ptr(8),
+			},
+		})
+	}
+
+	testCases := map[string]struct {
+		opts          []Option
+		expectSuccess bool
+	}{
+		"greedy fails": {
+			expectSuccess: false,
+		},
+		"exhaustive succeeds": {
+			opts:          []Option{WithSolver(SolverExhaustive)},
+			expectSuccess: true,
+		},
+		"exhaustive with a small budget": {
+			opts:          []Option{WithSolver(SolverExhaustive), WithSearchBudget(DefaultMaxPoolSetResults)},
+			expectSuccess: false,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			selected, _ := SelectNode(claims, testClasses(), testDrivers(), pools, tc.opts...)
+			allocations := selected.Allocations()
+			require.Equal(t, tc.expectSuccess, allocations != nil)
+			requireAllocationsFit(t, allocations, pools)
+
+			if !tc.expectSuccess {
+				return
+			}
+
+			for _, dcr := range selected.DeviceClaimResults {
+				shared := 0
+				for _, a := range dcr.Allocations() {
+					if strings.HasPrefix(a.DevicePoolName, "shared-") {
+						shared += a.DeviceCount
+					}
+				}
+				require.Equal(t, 2, shared, "claim %s", dcr.ClaimName)
+			}
+		})
+	}
+}
+
+func TestSelectNodeDeviceRange(t *testing.T) {
+	testCases := map[string]struct {
+		claim       api.DeviceClaimSpec
+		policy      AllocationPolicy
+		expectCount int
+
+		// scores are from the default FewestPoolsScorer, so a
+		// claim split across two pools loses a point
+		expectScore int
+	}{
+		"minimum policy stops at the minimum": {
+			claim: api.DeviceClaimSpec{
+				DeviceClass: "foozer-up-to-four",
+			},
+			policy:      AllocationPolicyMinimum,
+			expectCount: 1,
+			expectScore: 100,
+		},
+		"maximum policy takes up to the class maximum": {
+			claim: api.DeviceClaimSpec{
+				DeviceClass: "foozer-up-to-four",
+			},
+			policy:      AllocationPolicyMaximum,
+			expectCount: 4,
+			expectScore: 99,
+		},
+		"maximum policy takes up to the claim maximum": {
+			claim: api.DeviceClaimSpec{
+				DeviceClass:    "foozer-up-to-four",
+				MaxDeviceCount: ptr(3),
+			},
+			policy:      AllocationPolicyMaximum,
+			expectCount: 3,
+			expectScore: 99,
+		},
+		"maximum policy with no maximum takes everything": {
+			claim: api.DeviceClaimSpec{
+				DeviceClass: "gpu",
+				Driver:      ptr("example.com-foozer"),
+			},
+			policy:      AllocationPolicyMaximum,
+			expectCount: 4,
+			expectScore: 99,
+		},
+		"maximum policy with partial capacity": {
+			claim: api.DeviceClaimSpec{
+				DeviceClass:     "foozer-up-to-four",
+				MinDeviceCount:  ptr(2),
+				MatchAttributes: []string{"numa"},
+			},
+			policy:      AllocationPolicyMaximum,
+			expectCount: 2,
+			expectScore: 50,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			claims := []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: tc.claim,
+				},
+			}
+
+			selected, results := SelectNode(claims, testClasses(), testDrivers(), gen.GenShapeOne(1), WithAllocationPolicy(tc.policy))
+			allocations := selected.Allocations()
+			require.NotNil(t, allocations)
+			require.Len(t, results, 1)
+
+			count := 0
+			for _, a := range allocations {
+				count += a.DeviceCount
+			}
+			require.Equal(t, tc.expectCount, count)
+			require.Equal(t, tc.expectScore, results[0].Score())
+		})
+	}
+}
+
+func TestSelectNodeForSet(t *testing.T) {
+	foozer := api.DeviceClaimSpec{
+		DeviceClass:    "example.com-foozer",
+		MinDeviceCount: ptr(2),
+	}
+	barzer := api.DeviceClaimSpec{
+		DeviceClass:    "gpu",
+		Driver:         ptr("example.com-barzer"),
+		MinDeviceCount: ptr(2),
+	}
+
+	testCases := map[string]struct {
+		matchAttributes []string
+		claims          []api.DeviceClaimSpec
+		expectSuccess   bool
+	}{
+		"foozer and barzer on the same numa": {
+			matchAttributes: []string{"numa"},
+			claims:          []api.DeviceClaimSpec{foozer, barzer},
+			expectSuccess:   true,
+		},
+		"too many foozers for one numa": {
+			matchAttributes: []string{"numa"},
+			claims: []api.DeviceClaimSpec{
+				{
+					DeviceClass:    "example.com-foozer",
+					MinDeviceCount: ptr(3),
+				},
+				barzer,
+			},
+			expectSuccess: false,
+		},
+		"too many foozers without set match attributes": {
+			claims: []api.DeviceClaimSpec{
+				{
+					DeviceClass:    "example.com-foozer",
+					MinDeviceCount: ptr(3),
+				},
+				barzer,
+			},
+			expectSuccess: true,
+		},
+		"attribute missing from all pools": {
+			matchAttributes: []string{"pcie-root"},
+			claims:          []api.DeviceClaimSpec{foozer, barzer},
+			expectSuccess:   false,
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			// Use up the foozers on numa 0 and the barzers on numa 1, so
+			// that only numa 2 and 3 have both.
+			pools := gen.GenFoozerBarzerNodes(1)
+			for i := range pools {
+				switch pools[i].Name {
+				case "shape-foozer-barzer-00-foozer-00", "shape-foozer-barzer-00-barzer-01":
+					pools[i].Spec.DeviceCount = 0
+				}
+			}
+
+			setClaim := api.DeviceSetClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "myset",
+					Namespace: "default",
+				},
+				Spec: api.DeviceSetClaimSpec{
+					MatchAttributes: tc.matchAttributes,
+					ClaimSpec:       tc.claims,
+				},
+			}
+
+			status, results := SelectNodeForSet(setClaim, testClasses(), testDrivers(), pools)
+			require.Len(t, results, 1)
+			require.Equal(t, tc.expectSuccess, status != nil, results[0].Summary())
+			if !tc.expectSuccess {
+				return
+			}
+
+			require.Len(t, status.ClaimStatus, len(tc.claims))
+			require.Equal(t, "myset-0", results[0].DeviceClaimResults[0].ClaimName)
+			requireAllocationsFit(t, results[0].Allocations(), pools)
+
+			numas := make(map[string]bool)
+			for _, cs := range status.ClaimStatus {
+				require.NotEmpty(t, cs.Allocations)
+				for _, a := range cs.Allocations {
+					p := findPool(pools, a.DevicePoolName)
+					require.NotNil(t, p)
+					numas[attributeString(p.Spec.Attributes, "numa")] = true
+				}
+			}
+
+			if len(tc.matchAttributes) > 0 {
+				require.Len(t, numas, 1)
+				require.False(t, numas["0"] || numas["1"], "allocated from numa %v", numas)
+			}
+		})
+	}
+}
+
+func TestAllocatePrivileged(t *testing.T) {
+	testCases := map[string]struct {
+		claim       api.DevicePrivilegedClaimSpec
+		expectPools []string
+	}{
+		"all devices of the driver": {
+			claim: api.DevicePrivilegedClaimSpec{
+				Driver: "example.com-foozer",
+			},
+			expectPools: []string{
+				"shape-foozer-barzer-00-foozer-00",
+				"shape-foozer-barzer-00-foozer-01",
+				"shape-foozer-barzer-00-foozer-02",
+				"shape-foozer-barzer-00-foozer-03",
+			},
+		},
+		"with constraints": {
+			claim: api.DevicePrivilegedClaimSpec{
+				Driver:      "example.com-barzer",
+				Constraints: ptr("device.numa == '1'"),
+			},
+			expectPools: []string{
+				"shape-foozer-barzer-00-barzer-01",
+			},
+		},
+		"no matching driver": {
+			claim: api.DevicePrivilegedClaimSpec{
+				Driver: "sriov-nic",
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			pools := gen.GenFoozerBarzerNodes(2)
+			claim := api.DevicePrivilegedClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "monitor",
+					Namespace: "default",
+				},
+				Spec: tc.claim,
+			}
+
+			status, dcr := AllocatePrivileged(claim, "shape-foozer-barzer-00", pools)
+			require.True(t, dcr.Privileged)
+			if tc.expectPools == nil {
+				require.Nil(t, status)
+				require.NotEmpty(t, dcr.FailureReason)
+				return
+			}
+
+			var names []string
+			for _, a := range status.Allocations {
+				names = append(names, a.DevicePoolName)
+				require.Equal(t, 2, a.DeviceCount)
+			}
+			require.Equal(t, tc.expectPools, names)
+
+			// ordinary claims can still use all the devices
+			claims := []api.DeviceClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "everything",
+						Namespace: "default",
+					},
+					Spec: api.DeviceClaimSpec{
+						DeviceClass:    "gpu",
+						MinDeviceCount: ptr(16),
+					},
+				},
+			}
+			selected, _ := SelectNode(claims, testClasses(), testDrivers(), pools)
+			allocations := selected.Allocations()
+			require.NotNil(t, allocations)
+		})
+	}
+}
+
+func TestSelectNodeForPod(t *testing.T) {
+	foozers := func(count int) api.DeviceClaimSpec {
+		return api.DeviceClaimSpec{
+			DeviceClass:    "example.com-foozer",
+			MinDeviceCount: ptr(count),
+		}
+	}
+	barzers := api.DeviceClaimSpec{
+		DeviceClass:    "gpu",
+		Driver:         ptr("example.com-barzer"),
+		MinDeviceCount: ptr(2),
+	}
+
+	testCases := map[string]struct {
+		claims        []api.DeviceClaimSpec
+		set           []api.DeviceClaimSpec
+		privileged    *api.DevicePrivilegedClaimSpec
+		expectSuccess bool
+	}{
+		"claim and set": {
+			claims:        []api.DeviceClaimSpec{foozers(2)},
+			set:           []api.DeviceClaimSpec{foozers(2), barzers},
+			expectSuccess: true,
+		},
+		"set does not fit beside the claim": {
+			claims: []api.DeviceClaimSpec{foozers(8)},
+			set:    []api.DeviceClaimSpec{foozers(2), barzers},
+		},
+		"privileged claim of the claimed devices": {
+			claims:        []api.DeviceClaimSpec{foozers(8)},
+			privileged:    &api.DevicePrivilegedClaimSpec{Driver: "example.com-foozer"},
+			expectSuccess: true,
+		},
+		"privileged claim without devices": {
+			claims:     []api.DeviceClaimSpec{foozers(2)},
+			privileged: &api.DevicePrivilegedClaimSpec{Driver: "sriov-nic"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			pools := gen.GenFoozerBarzerNodes(1)
+
+			var claims PodClaims
+			for i, spec := range tc.claims {
+				claims.Claims = append(claims.Claims, api.DeviceClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("claim-%d", i), Namespace: "default"},
+					Spec:       spec,
+				})
+			}
+			if tc.set != nil {
+				claims.SetClaims = append(claims.SetClaims, api.DeviceSetClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: "myset", Namespace: "default"},
+					Spec: api.DeviceSetClaimSpec{
+						MatchAttributes: []string{"numa"},
+						ClaimSpec:       tc.set,
+					},
+				})
+			}
+			if tc.privileged != nil {
+				claims.PrivilegedClaims = append(claims.PrivilegedClaims, api.DevicePrivilegedClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+					Spec:       *tc.privileged,
+				})
+			}
+
+			selected, results := SelectNodeForPod(claims, testClasses(), testDrivers(), pools, pools)
+			require.Len(t, results, 1)
+			require.Equal(t, tc.expectSuccess, selected != nil, results[0].Summary())
+			if !tc.expectSuccess {
+				return
+			}
+
+			// The results are in the order of the claims, then the
+			// members of the set, then the privileged claims.
+			var names []string
+			for _, dcr := range selected.DeviceClaimResults {
+				names = append(names, dcr.ClaimName)
+				require.Equal(t, dcr.ClaimName == "monitor", dcr.Privileged)
+			}
+			var expectNames []string
+			for _, c := range claims.Claims {
+				expectNames = append(expectNames, c.Name)
+			}
+			for i := range tc.set {
+				expectNames = append(expectNames, fmt.Sprintf("myset-%d", i))
+			}
+			if tc.privileged != nil {
+				expectNames = append(expectNames, "monitor")
+			}
+			require.Equal(t, expectNames, names)
+
+			// The set shares the pools with the claims, but the
+			// privileged claim does not consume any devices.
+			requireAllocationsFit(t, selected.Allocations(), pools)
+
+			if tc.set != nil {
+				numas := make(map[string]bool)
+				for _, dcr := range selected.DeviceClaimResults[len(tc.claims):] {
+					for _, a := range dcr.Allocations() {
+						numas[attributeString(findPool(pools, a.DevicePoolName).Spec.Attributes, "numa")] = true
+					}
+				}
+				require.Len(t, numas, 1)
+			}
+		})
+	}
+}
+
+// TestSelectNodeForPodPrivilegedScore checks that a privileged claim does not
+// change the score of the node.
+func TestSelectNodeForPodPrivilegedScore(t *testing.T) {
+	pools := gen.GenFoozerBarzerNodes(1)
+	claims := PodClaims{
+		Claims: []api.DeviceClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default"},
+				Spec: api.DeviceClaimSpec{
+					DeviceClass:    "example.com-foozer",
+					MinDeviceCount: ptr(8),
+				},
+			},
+		},
+	}
+
+	for _, scorer := range []Scorer{FewestPoolsScorer{}, MostAllocatedScorer{}, LeastAllocatedScorer{}, TopologyScorer{Attributes: []string{"numa"}}} {
+		selected, _ := SelectNodeForPod(claims, testClasses(), testDrivers(), pools, pools, WithScorer(scorer))
+		require.NotNil(t, selected)
+		score := selected.Score()
+		require.Less(t, score, 100, "%T", scorer)
+
+		withPrivileged := claims
+		withPrivileged.PrivilegedClaims = []api.DevicePrivilegedClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+				Spec:       api.DevicePrivilegedClaimSpec{Driver: "example.com-foozer"},
+			},
+		}
+		selected, _ = SelectNodeForPod(withPrivileged, testClasses(), testDrivers(), pools, pools, WithScorer(scorer))
+		require.NotNil(t, selected)
+		require.Equal(t, score, selected.Score(), "%T", scorer)
+	}
+}
+
+func TestSelectNodeNetworkPools(t *testing.T) {
+	nodeLabels := map[string]map[string]string{
+		"shape-one-00": {"rack": "a"},
+		"shape-one-01": {"rack": "b"},
+	}
+
+	testCases := map[string]struct {
+		pool        api.DevicePoolSpec
+		nodeLabels  map[string]map[string]string
+		expectNodes []string
+	}{
+		"reachable from all nodes": {
+			expectNodes: []string{"shape-one-00", "shape-one-01"},
+		},
+		"node selector": {
+			pool: api.DevicePoolSpec{
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"rack": "b"},
+				},
+			},
+			nodeLabels:  nodeLabels,
+			expectNodes: []string{"shape-one-01"},
+		},
+		"node selector without node labels": {
+			pool: api.DevicePoolSpec{
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"rack": "b"},
+				},
+			},
+		},
+		"node selector matching a node with no local pools": {
+			pool: api.DevicePoolSpec{
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"rack": "c"},
+				},
+			},
+			nodeLabels: map[string]map[string]string{
+				"storage-node": {"rack": "c"},
+			},
+			expectNodes: []string{"storage-node"},
+		},
+		"reachability attributes": {
+			pool: api.DevicePoolSpec{
+				Attributes: []api.Attribute{
+					{Name: "rack", StringValue: ptr("a")},
+				},
+				ReachabilityAttributes: []string{"rack"},
+			},
+			expectNodes: []string{"shape-one-00"},
+		},
+	}
+
+	claims := []api.DeviceClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "barzer-claim",
+				Namespace: "default",
+			},
+			Spec: api.DeviceClaimSpec{
+				DeviceClass:    "gpu",
+				Driver:         ptr("example.com-barzer"),
+				MinDeviceCount: ptr(3),
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			pools := networkTestPools(tc.pool)
+			selected, results := SelectNode(claims, testClasses(), testDrivers(), pools, WithNodeLabels(tc.nodeLabels))
+			allocations := selected.Allocations()
+			require.Equal(t, len(tc.expectNodes) > 0, allocations != nil)
+
+			var nodes []string
+			for _, nr := range results {
+				if nr.Score() > 0 {
+					nodes = append(nodes, nr.NodeName)
+				}
+			}
+			require.ElementsMatch(t, tc.expectNodes, nodes)
+
+			if allocations != nil {
+				require.Equal(t, []api.DevicePoolAllocation{
+					{DevicePoolName: "network-barzer", DeviceCount: 3},
+				}, allocations)
+			}
+		})
+	}
+
+	t.Run("debited once across claims", func(t *testing.T) {
+		twoClaims := append([]api.DeviceClaim{}, claims[0], claims[0])
+		twoClaims[1].Name = "another-barzer-claim"
+
+		selected, _ := SelectNode(twoClaims, testClasses(), testDrivers(), networkTestPools(api.DevicePoolSpec{}))
+		allocations := selected.Allocations()
+		require.Nil(t, allocations)
+	})
+
+	t.Run("debited once across nodes", func(t *testing.T) {
+		pools := networkTestPools(api.DevicePoolSpec{})
+		selected, _ := SelectNode(claims, testClasses(), testDrivers(), pools)
+		allocations := selected.Allocations()
+		require.NotNil(t, allocations)
+
+		// once applied, the devices are not available from any node
+		applyAllocations(pools, allocations)
+		selected, _ = SelectNode(claims, testClasses(), testDrivers(), pools)
+		allocations = selected.Allocations()
+		require.Nil(t, allocations)
+	})
+}
+
+// networkTestPools returns two nodes with local foozers, in racks "a" and "b",
+// and a network attached pool of four barzers using the passed spec.
+func networkTestPools(spec api.DevicePoolSpec) []api.DevicePool {
+	pools := gen.GenShapeOne(2)
+	for i := range pools {
+		rack := "a"
+		if *pools[i].Spec.NodeName == "shape-one-01" {
+			rack = "b"
+		}
+		pools[i].Spec.Attributes = append(pools[i].Spec.Attributes, api.Attribute{Name: "rack", StringValue: ptr(rack)})
+	}
+
+	spec.Driver = "example.com-barzer"
+	spec.DeviceCount = 4
+
+	return append(pools, api.DevicePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "network-barzer"},
+		Spec:       spec,
+	})
+}
@@ -0,0 +1,204 @@
+package schedule
+
+import (
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+)
+
+// Scorer is used to compare the different ways claims may be satisfied. It is
+// called for each successful pool set for a claim, and for each node on which
+// all claims were satisfied. Higher scores are better. Scores are clamped to
+// the range 1 to 100, since a zero score means failure.
+type Scorer interface {
+	// ScorePoolSet returns the score for satisfying the claim with the
+	// pool set. The pools contain their available devices prior to this
+	// allocation, and the Score in the passed result is the percentage of
+	// the requested devices that were allocated.
+	ScorePoolSet(claim api.DeviceClaim, pools []api.DevicePool, psr PoolSetResult) int
+
+	// ScoreNode returns the score for the node, given the results for all
+	// the claims. The pools are all the pools on the node, with their
+	// available devices prior to any of these allocations.
+	ScoreNode(nr *NodeResult, pools []api.DevicePool) int
+}
+
+// FewestPoolsScorer prefers solutions that use the fewest pools. This is the
+// default.
+type FewestPoolsScorer struct{}
+
+var _ Scorer = FewestPoolsScorer{}
+
+// ScorePoolSet takes one point off for each additional pool, so that getting
+// more of the requested devices always wins over using fewer pools.
+func (FewestPoolsScorer) ScorePoolSet(claim api.DeviceClaim, pools []api.DevicePool, psr PoolSetResult) int {
+	return psr.Score - (len(psr.PoolResults) - 1)
+}
+
+// ScoreNode averages the claim scores. Privileged claims are left out, as
+// they do not consume the devices.
+func (FewestPoolsScorer) ScoreNode(nr *NodeResult, pools []api.DevicePool) int {
+	sum, count := 0, 0
+	for _, dcr := range nr.DeviceClaimResults {
+		if dcr.Privileged {
+			continue
+		}
+
+		sum += dcr.Score()
+		count++
+	}
+
+	if count == 0 {
+		return 100
+	}
+
+	return sum / count
+}
+
+// MostAllocatedScorer prefers pools and nodes with the fewest available
+// devices remaining, packing claims onto as few of them as possible.
+type MostAllocatedScorer struct{}
+
+var _ Scorer = MostAllocatedScorer{}
+
+func (MostAllocatedScorer) ScorePoolSet(claim api.DeviceClaim, pools []api.DevicePool, psr PoolSetResult) int {
+	return psr.Score * allocatedPercent(psr.Allocations(), pools) / 100
+}
+
+func (MostAllocatedScorer) ScoreNode(nr *NodeResult, pools []api.DevicePool) int {
+	return allocatedPercent(nr.Allocations(), pools)
+}
+
+// LeastAllocatedScorer prefers pools and nodes with the most available
+// devices remaining, spreading claims across them.
+type LeastAllocatedScorer struct{}
+
+var _ Scorer = LeastAllocatedScorer{}
+
+func (LeastAllocatedScorer) ScorePoolSet(claim api.DeviceClaim, pools []api.DevicePool, psr PoolSetResult) int {
+	return psr.Score * (100 - allocatedPercent(psr.Allocations(), pools)) / 100
+}
+
+func (LeastAllocatedScorer) ScoreNode(nr *NodeResult, pools []api.DevicePool) int {
+	return 100 - allocatedPercent(nr.Allocations(), pools)
+}
+
+// TopologyScorer prefers solutions in which all the allocated pools share
+// the same values for the listed attributes; for example, "numa". Unlike
+// MatchAttributes, this is a preference rather than a requirement, and at the
+// node level it applies across all the claims.
+type TopologyScorer struct {
+	Attributes []string
+}
+
+var _ Scorer = TopologyScorer{}
+
+func (ts TopologyScorer) ScorePoolSet(claim api.DeviceClaim, pools []api.DevicePool, psr PoolSetResult) int {
+	return psr.Score / ts.topologyGroups(psr.Allocations(), pools)
+}
+
+func (ts TopologyScorer) ScoreNode(nr *NodeResult, pools []api.DevicePool) int {
+	return 100 / ts.topologyGroups(nr.Allocations(), pools)
+}
+
+// topologyGroups returns the number of distinct combinations of values of the
+// topology attributes among the allocated pools.
+func (ts TopologyScorer) topologyGroups(allocations []api.DevicePoolAllocation, pools []api.DevicePool) int {
+	groups := make(map[string]bool)
+	for _, a := range allocations {
+		p := findPool(pools, a.DevicePoolName)
+		if p == nil {
+			continue
+		}
+
+		groups[matchAttributesKey(ts.Attributes, p.Spec.Attributes)] = true
+	}
+
+	if len(groups) == 0 {
+		return 1
+	}
+
+	return len(groups)
+}
+
+// allocatedPercent returns the percentage of the available devices in the
+// pools that are consumed by the allocations.
+func allocatedPercent(allocations []api.DevicePoolAllocation, pools []api.DevicePool) int {
+	available := 0
+	for _, p := range pools {
+		available += p.Spec.DeviceCount
+	}
+
+	if available <= 0 {
+		return 100
+	}
+
+	allocated := 0
+	for _, a := range allocations {
+		allocated += a.DeviceCount
+	}
+
+	return 100 * allocated / available
+}
+
+// attributeString returns the value of the named attribute as a string,
+// tagged with its type, so that values of different types differ. Quantities
+// are in a canonical form, so that quantities that are equal, such as 1Gi
+// and 1073741824, are the same. Missing attributes have their own tag.
+func attributeString(attrs []api.Attribute, name string) string {
+	for _, a := range attrs {
+		if a.Name != name {
+			continue
+		}
+
+		switch {
+		case a.StringValue != nil:
+			return "s:" + *a.StringValue
+		case a.IntValue != nil:
+			return "i:" + strconv.Itoa(*a.IntValue)
+		case a.QuantityValue != nil:
+			return "q:" + canonicalQuantity(*a.QuantityValue)
+		case a.SemVerValue != nil:
+			return "v:" + string(*a.SemVerValue)
+		}
+	}
+
+	return "-"
+}
+
+// canonicalQuantity returns the exact decimal value of the quantity, without
+// trailing zeros.
+func canonicalQuantity(q resource.Quantity) string {
+	value := q.AsDec().String()
+	if strings.Contains(value, ".") {
+		value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+	}
+
+	return value
+}
+
+func findPool(pools []api.DevicePool, name string) *api.DevicePool {
+	for i := range pools {
+		if pools[i].Name == name {
+			return &pools[i]
+		}
+	}
+
+	return nil
+}
+
+// clampScore keeps the score of a successful result within 1 to 100.
+func clampScore(score int) int {
+	if score < 1 {
+		return 1
+	}
+
+	if score > 100 {
+		return 100
+	}
+
+	return score
+}
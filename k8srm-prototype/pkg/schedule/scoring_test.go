@@ -0,0 +1,232 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/gen"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestScorers(t *testing.T) {
+	// Two nodes with a single pool of two foozers each, but one of the
+	// devices on shape-zero-01 is already allocated.
+	partiallyAllocated := gen.GenShapeZero(2)
+	partiallyAllocated[1].Spec.DeviceCount = 1
+
+	testCases := map[string]struct {
+		scorer     Scorer
+		claims     []api.DeviceClaimSpec
+		pools      []api.DevicePool
+		expectNode string
+		expectPool []string
+	}{
+		"most allocated packs onto the fuller node": {
+			scorer: MostAllocatedScorer{},
+			claims: []api.DeviceClaimSpec{
+				{DeviceClass: "example.com-foozer"},
+			},
+			pools:      partiallyAllocated,
+			expectNode: "shape-zero-01",
+		},
+		"least allocated spreads onto the emptier node": {
+			scorer: LeastAllocatedScorer{},
+			claims: []api.DeviceClaimSpec{
+				{DeviceClass: "example.com-foozer"},
+			},
+			pools:      partiallyAllocated,
+			expectNode: "shape-zero-00",
+		},
+		"least allocated spreads across pools": {
+			scorer: LeastAllocatedScorer{},
+			claims: []api.DeviceClaimSpec{
+				{DeviceClass: "example.com-foozer"},
+				{DeviceClass: "example.com-foozer"},
+			},
+			pools:      gen.GenShapeOne(1),
+			expectNode: "shape-one-00",
+			expectPool: []string{"shape-one-00-foozer-00", "shape-one-00-foozer-01"},
+		},
+		"fewest pools packs into one pool": {
+			scorer: FewestPoolsScorer{},
+			claims: []api.DeviceClaimSpec{
+				{DeviceClass: "example.com-foozer"},
+				{DeviceClass: "example.com-foozer"},
+			},
+			pools:      gen.GenShapeOne(1),
+			expectNode: "shape-one-00",
+			expectPool: []string{"shape-one-00-foozer-00", "shape-one-00-foozer-00"},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			var claims []api.DeviceClaim
+			for _, spec := range tc.claims {
+				claims = append(claims, api.DeviceClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "myclaim",
+						Namespace: "default",
+					},
+					Spec: spec,
+				})
+			}
+
+			selected, _ := SelectNode(claims, testClasses(), testDrivers(), tc.pools, WithScorer(tc.scorer))
+			require.NotNil(t, selected)
+			require.Equal(t, tc.expectNode, selected.NodeName)
+			allocations := selected.Allocations()
+
+			if tc.expectPool != nil {
+				var pools []string
+				for _, a := range allocations {
+					pools = append(pools, a.DevicePoolName)
+				}
+				require.Equal(t, tc.expectPool, pools)
+			}
+		})
+	}
+}
+
+func TestTopologyScorer(t *testing.T) {
+	pools := gen.GenFoozerBarzerNodes(1)
+	scorer := TopologyScorer{Attributes: []string{"numa"}}
+
+	sameNuma := NodeResult{
+		DeviceClaimResults: []DeviceClaimResult{
+			{
+				Best: 0,
+				PoolSetResults: []PoolSetResult{
+					{
+						Score:       100,
+						PoolResults: []PoolResult{{PoolName: "shape-foozer-barzer-00-foozer-00", DeviceCount: 1}},
+					},
+				},
+			},
+			{
+				Best: 0,
+				PoolSetResults: []PoolSetResult{
+					{
+						Score:       100,
+						PoolResults: []PoolResult{{PoolName: "shape-foozer-barzer-00-barzer-00", DeviceCount: 1}},
+					},
+				},
+			},
+		},
+	}
+
+	differentNuma := NodeResult{
+		DeviceClaimResults: []DeviceClaimResult{
+			sameNuma.DeviceClaimResults[0],
+			{
+				Best: 0,
+				PoolSetResults: []PoolSetResult{
+					{
+						Score:       100,
+						PoolResults: []PoolResult{{PoolName: "shape-foozer-barzer-00-barzer-01", DeviceCount: 1}},
+					},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, 100, scorer.ScoreNode(&sameNuma, pools))
+	require.Equal(t, 50, scorer.ScoreNode(&differentNuma, pools))
+
+	psr := PoolSetResult{
+		Score: 100,
+		PoolResults: []PoolResult{
+			{PoolName: "shape-foozer-barzer-00-foozer-00", DeviceCount: 2},
+			{PoolName: "shape-foozer-barzer-00-foozer-01", DeviceCount: 2},
+		},
+	}
+	require.Equal(t, 50, scorer.ScorePoolSet(api.DeviceClaim{}, pools, psr))
+}
+
+func TestTopologyScorerDistinctValues(t *testing.T) {
+	// Joined with a separator, the values of these pools would be the same.
+	pools := []api.DevicePool{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-a"},
+			Spec: api.DevicePoolSpec{
+				DeviceCount: 1,
+				Attributes: []api.Attribute{
+					{Name: "rack", StringValue: ptr("r1/r2")},
+					{Name: "row", StringValue: ptr("r3")},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-b"},
+			Spec: api.DevicePoolSpec{
+				DeviceCount: 1,
+				Attributes: []api.Attribute{
+					{Name: "rack", StringValue: ptr("r1")},
+					{Name: "row", StringValue: ptr("r2/r3")},
+				},
+			},
+		},
+	}
+
+	names := []string{"rack", "row"}
+	require.NotEqual(t, matchAttributesKey(names, pools[0].Spec.Attributes), matchAttributesKey(names, pools[1].Spec.Attributes))
+
+	scorer := TopologyScorer{Attributes: names}
+	allocations := []api.DevicePoolAllocation{
+		{DevicePoolName: "pool-a", DeviceCount: 1},
+		{DevicePoolName: "pool-b", DeviceCount: 1},
+	}
+	require.Equal(t, 2, scorer.topologyGroups(allocations, pools))
+}
+
+// TestScorersSharedNetworkPools checks that the selected node is the one with
+// the best score, even when the claims are satisfied from a network attached
+// pool, so that every node has the same allocations.
+func TestScorersSharedNetworkPools(t *testing.T) {
+	pools := []api.DevicePool{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "network-foozer"},
+			Spec:       api.DevicePoolSpec{Driver: "example.com-foozer", DeviceCount: 2},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a-nic"},
+			Spec:       api.DevicePoolSpec{NodeName: ptr("node-a"), Driver: "sriov-nic", DeviceCount: 12},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-b-nic"},
+			Spec:       api.DevicePoolSpec{NodeName: ptr("node-b"), Driver: "sriov-nic", DeviceCount: 3},
+		},
+	}
+
+	claims := []api.DeviceClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myclaim",
+				Namespace: "default",
+			},
+			Spec: api.DeviceClaimSpec{DeviceClass: "example.com-foozer"},
+		},
+	}
+
+	// One of 14 devices reachable from node-a is allocated, and one of 5
+	// from node-b.
+	expectScores := map[string]int{"node-a": 7, "node-b": 20}
+
+	// The nodes are evaluated in random order, so try a few times.
+	for i := 0; i < 10; i++ {
+		selected, results := SelectNode(claims, testClasses(), testDrivers(), pools, WithScorer(MostAllocatedScorer{}))
+		require.NotNil(t, selected)
+		require.Equal(t, "node-b", selected.NodeName)
+
+		scores := make(map[string]int)
+		for _, nr := range results {
+			scores[nr.NodeName] = nr.Score()
+			require.Equal(t, []api.DevicePoolAllocation{
+				{DevicePoolName: "network-foozer", DeviceCount: 1},
+			}, nr.Allocations())
+		}
+		require.Equal(t, expectScores, scores)
+	}
+}
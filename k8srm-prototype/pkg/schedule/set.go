@@ -0,0 +1,125 @@
+package schedule
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+)
+
+// SelectNodeForSet will select the node that can best satisfy all the claims in
+// the DeviceSetClaim, such that all the pools allocated for the set have the
+// same values for the set MatchAttributes. Pools that do not have all of the
+// set MatchAttributes cannot be used for the set.
+//
+// This works the same way as SelectNode, and takes the same options. Each
+// member claim is named after the set and its index in the set, which is also
+// the order of the DeviceClaimResults of each node.
+//
+// The first returned value is the status for the set, with the allocations for
+// each member claim in the same order as the claims in the set. In the event no
+// node can be selected, this will be nil. The second returned value is an array
+// of the results of evaluating each node.
+func SelectNodeForSet(setClaim api.DeviceSetClaim, classes []api.DeviceClass, drivers []api.DeviceDriver, pools []api.DevicePool, opts ...Option) (*api.DeviceSetClaimStatus, []NodeResult) {
+	o := buildOptions(opts)
+	claims := setMemberClaims(setClaim)
+	classesByName := classesByName(classes)
+
+	var results []NodeResult
+	for node, nodeDevPools := range poolsByNode(pools, o.nodeLabels) {
+		results = append(results, evaluateNodeForSet(node, setClaim.Spec.MatchAttributes, claims, classesByName, drivers, nodeDevPools, o))
+	}
+
+	best := bestNode(results)
+	if best == -1 {
+		return nil, results
+	}
+
+	status := &api.DeviceSetClaimStatus{}
+	for _, dcr := range results[best].DeviceClaimResults {
+		status.ClaimStatus = append(status.ClaimStatus, api.DeviceClaimStatus{
+			Allocations: dcr.Allocations(),
+		})
+	}
+
+	return status, results
+}
+
+// setMemberClaims returns a DeviceClaim for each claim spec in the set.
+func setMemberClaims(setClaim api.DeviceSetClaim) []api.DeviceClaim {
+	var claims []api.DeviceClaim
+	for i, spec := range setClaim.Spec.ClaimSpec {
+		c := api.DeviceClaim{Spec: spec}
+		c.Name = fmt.Sprintf("%s-%d", setClaim.Name, i)
+		c.Namespace = setClaim.Namespace
+		claims = append(claims, c)
+	}
+
+	return claims
+}
+
+// evaluateNodeForSet splits the pools on the node into groups that share the
+// values of the set MatchAttributes, and evaluates the claims against each
+// group. The result for the best group is returned. If no group satisfies
+// the claims, the result for the first group is returned, so that the
+// failures are reported.
+func evaluateNodeForSet(node string, matchAttributes []string, claims []api.DeviceClaim, classes map[string]*api.DeviceClass, drivers []api.DeviceDriver, pools []api.DevicePool, o *options) NodeResult {
+	var groupKeys []string
+	groups := make(map[string][]api.DevicePool)
+	for _, p := range pools {
+		if !hasAttributes(p.Spec.Attributes, matchAttributes) {
+			continue
+		}
+
+		key := matchAttributesKey(matchAttributes, p.Spec.Attributes)
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], p)
+	}
+
+	if len(groupKeys) == 0 {
+		nr := NodeResult{
+			NodeName: node,
+			Solver:   SolverGreedy,
+		}
+		for _, c := range claims {
+			nr.DeviceClaimResults = append(nr.DeviceClaimResults, DeviceClaimResult{
+				ClaimName:     c.Name,
+				Best:          -1,
+				FailureReason: "no pools have the set MatchAttributes",
+			})
+		}
+
+		return nr
+	}
+
+	var results []NodeResult
+	for _, key := range groupKeys {
+		results = append(results, evaluateNode(node, claims, classes, drivers, groups[key], o))
+	}
+
+	best := bestNode(results)
+	if best == -1 {
+		return results[0]
+	}
+
+	return results[best]
+}
+
+func hasAttributes(attrs []api.Attribute, names []string) bool {
+	for _, name := range names {
+		found := false
+		for _, a := range attrs {
+			if a.Name == name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
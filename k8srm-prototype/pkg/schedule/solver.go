@@ -0,0 +1,120 @@
+package schedule
+
+import (
+	"sort"
+
+	"github.com/kubernetes-sigs/wg-device-management/k8srm-prototype/pkg/api"
+)
+
+// claimSearch holds the state of an exhaustive search for an assignment of
+// pool sets to claims on a single node.
+type claimSearch struct {
+	claims  []api.DeviceClaim
+	classes map[string]*api.DeviceClass
+	drivers []api.DeviceDriver
+	opts    *options
+
+	// budget is the number of claim evaluations remaining
+	budget int
+}
+
+// searchNode looks for a way to satisfy all the claims with the pools, trying
+// every ordering of the claims and every successful pool set for each claim.
+// It returns the results in the same order as the claims, or nil if no
+// solution was found within the budget.
+//
+// The candidates for each claim are the pool sets returned by the allocator.
+// A PoolSetAllocator only returns the best MaxResults pool sets, which need
+// not include the one that leaves room for the other claims, so the search
+// raises that limit to the budget. The pool sets are still only those of the
+// smallest size that satisfies the claim; larger sets are not considered.
+// Other allocators are used as configured.
+func searchNode(claims []api.DeviceClaim, classes map[string]*api.DeviceClass, drivers []api.DeviceDriver, pools []api.DevicePool, o *options) []DeviceClaimResult {
+	if a, ok := o.allocator.(PoolSetAllocator); ok && a.maxResults() < o.searchBudget {
+		a.MaxResults = o.searchBudget
+		searchOpts := *o
+		searchOpts.allocator = a
+		o = &searchOpts
+	}
+
+	s := &claimSearch{
+		claims:  claims,
+		classes: classes,
+		drivers: drivers,
+		opts:    o,
+		budget:  o.searchBudget,
+	}
+
+	results := make([]DeviceClaimResult, len(claims))
+	done := make([]bool, len(claims))
+	if !s.search(pools, results, done, len(claims)) {
+		return nil
+	}
+
+	return results
+}
+
+// search tries to satisfy the remaining claims with the available pools. On
+// success, the results of each claim have Best set to the chosen pool set.
+//
+// Note that this will consider the same assignment more than once, reached
+// via different claim orderings. Rather than try to be clever about that, we
+// rely on the budget to bound the search.
+func (s *claimSearch) search(available []api.DevicePool, results []DeviceClaimResult, done []bool, remaining int) bool {
+	if remaining == 0 {
+		return true
+	}
+
+	for i, c := range s.claims {
+		if done[i] {
+			continue
+		}
+
+		if s.budget <= 0 {
+			return false
+		}
+		s.budget--
+
+		dcr := evaluateNodeForClaim(c, s.classes[c.Spec.DeviceClass], s.drivers, available, s.opts)
+
+		// Availability only goes down as we go deeper, so if this claim
+		// cannot be satisfied now, it cannot be satisfied in any other
+		// ordering from this point either.
+		if dcr.Best == -1 {
+			return false
+		}
+
+		for _, candidate := range candidatePoolSets(dcr) {
+			next := make([]api.DevicePool, len(available))
+			copy(next, available)
+			applyAllocations(next, dcr.PoolSetResults[candidate].Allocations())
+
+			dcr.Best = candidate
+			results[i] = dcr
+			done[i] = true
+			if s.search(next, results, done, remaining-1) {
+				return true
+			}
+			done[i] = false
+		}
+	}
+
+	return false
+}
+
+// candidatePoolSets returns the indices of the successful pool sets for the
+// claim, highest score first.
+func candidatePoolSets(dcr DeviceClaimResult) []int {
+	var candidates []int
+	for i, psr := range dcr.PoolSetResults {
+		if psr.Score > 0 {
+			candidates = append(candidates, i)
+		}
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return dcr.PoolSetResults[candidates[a]].Score > dcr.PoolSetResults[candidates[b]].Score
+	})
+
+	return candidates
+}